@@ -1,11 +1,15 @@
 package main
 
 import (
+	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
+	smartling "github.com/Smartling/api-sdk-go"
 	"github.com/reconquest/hierr-go"
 )
 
@@ -43,6 +47,75 @@ func compileFormat(definition string) (*Format, error) {
 		"ext": func(path string) string {
 			return filepath.Ext(path)
 		},
+
+		"dir": func(uri string) string {
+			return path.Dir(uri)
+		},
+
+		"base": func(uri string) string {
+			return path.Base(uri)
+		},
+
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+
+		// arguments are ordered so functions can be used in pipelines like
+		// {{.FileURI | replace "/" "_"}}
+		"replace": func(old, new, value string) string {
+			return strings.ReplaceAll(value, old, new)
+		},
+
+		"trimPrefix": func(prefix, value string) string {
+			return strings.TrimPrefix(value, prefix)
+		},
+
+		"trimSuffix": func(suffix, value string) string {
+			return strings.TrimSuffix(value, suffix)
+		},
+
+		// date accepts both timestamps of format data and timestamps of
+		// API objects, like smartling.File in files list
+		"date": func(layout string, value interface{}) (string, error) {
+			switch value := value.(type) {
+			case time.Time:
+				return value.Format(layout), nil
+			case smartling.UTC:
+				return value.Format(layout), nil
+			default:
+				return "", fmt.Errorf(
+					"date: unsupported value type %T",
+					value,
+				)
+			}
+		},
+
+		"language": func(locale string) string {
+			return parseLocale(locale).Language
+		},
+
+		"script": func(locale string) string {
+			return parseLocale(locale).Script
+		},
+
+		"region": func(locale string) string {
+			return parseLocale(locale).Region
+		},
+
+		"posix": func(locale string) string {
+			return parseLocale(locale).POSIX()
+		},
+
+		"android": func(locale string) string {
+			return parseLocale(locale).Android()
+		},
+
+		"ios": func(locale string) string {
+			return parseLocale(locale).IOS()
+		},
+
+		"java": func(locale string) string {
+			return parseLocale(locale).Java()
+		},
 	}
 
 	var (
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gobwas/glob"
-	"github.com/imdario/mergo"
 	"github.com/kovetskiy/ko"
 	"github.com/reconquest/hierr-go"
-	"gopkg.in/yaml.v2"
 )
 
+// defaultFileSection is a key of files config section, which applies to all
+// files.
+const defaultFileSection = "default"
+
 type FileConfig struct {
 	Pull struct {
 		Format string `yaml:"format,omitempty"`
@@ -19,29 +26,112 @@ type FileConfig struct {
 		Type       string            `yaml:"type,omitempty"`
 		Directives map[string]string `yaml:"directives,omitempty,flow"`
 	} `yaml:"push,omitempty"`
+	// Locales overrides global locales mapping for matching files.
+	Locales []LocaleConfig `yaml:"locales,omitempty"`
 }
 
 type LocaleConfig struct {
-	Application string `yaml:"application",required:"true"`
-	Smartling   string `yaml:"smartling",required:"true"`
+	Application string `yaml:"application" required:"true"`
+	Smartling   string `yaml:"smartling" required:"true"`
 }
 
 type Config struct {
-	UserID    string `yaml:"user_id",required:"true"`
-	Secret    string `yaml:"secret",required:"true"`
+	// UserID and Secret are required, but can be specified by environment
+	// variables or command line options, so they are checked after loading.
+	UserID string `yaml:"user_id"`
+	Secret string `yaml:"secret"`
+
+	// SecretFile, SecretCommand and SecretEnv specify external sources of
+	// token secret, so it's not stored in config file.
+	SecretFile    string `yaml:"secret_file,omitempty"`
+	SecretCommand string `yaml:"secret_command,omitempty"`
+	SecretEnv     string `yaml:"secret_env,omitempty"`
+
 	AccountID string `yaml:"account_id"`
 	ProjectID string `yaml:"project_id,omitempty"`
 	Threads   int    `yaml:"threads"`
 
-	Locales   []LocaleConfig `yaml:"locales"`
-	LocaleToAppLocaleMap   map[string]string
-	AppLocaleToLocaleMap   map[string]string
+	Locales              []LocaleConfig    `yaml:"locales"`
+	LocaleToAppLocaleMap map[string]string `yaml:"-"`
+	AppLocaleToLocaleMap map[string]string `yaml:"-"`
 
 	Files map[string]FileConfig `yaml:"files"`
 
-	Proxy string `yaml:"proxy,omitempty"`
+	Proxy        string `yaml:"proxy,omitempty"`
+	SmartlingURL string `yaml:"smartling_url,omitempty"`
+
+	Retry     RetryConfig     `yaml:"retry,omitempty"`
+	RateLimit RateLimitConfig `yaml:"rate_limit,omitempty"`
+
+	Profiles map[string]ProfileConfig `yaml:"profiles,omitempty"`
+
+	// Profile is name of profile applied to config, if any.
+	Profile string `yaml:"-"`
 
 	path string
+
+	// origins describe where values came from, keyed by YAML key
+	origins map[string]string
+
+	// secretError is error of resolving secret from external source, which
+	// is reported only when secret is required and not found elsewhere
+	secretError error
+}
+
+// ProfileConfig overrides base config values when profile is selected via
+// --profile option or SMARTLING_PROFILE environment variable.
+type ProfileConfig struct {
+	UserID    string `yaml:"user_id,omitempty"`
+	Secret    string `yaml:"secret,omitempty"`
+	AccountID string `yaml:"account_id,omitempty"`
+
+	SecretFile    string `yaml:"secret_file,omitempty"`
+	SecretCommand string `yaml:"secret_command,omitempty"`
+	SecretEnv     string `yaml:"secret_env,omitempty"`
+
+	ProjectID    string `yaml:"project_id,omitempty"`
+	Threads      int    `yaml:"threads,omitempty"`
+	Proxy        string `yaml:"proxy,omitempty"`
+	SmartlingURL string `yaml:"smartling_url,omitempty"`
+
+	Locales []LocaleConfig `yaml:"locales,omitempty"`
+}
+
+type RateLimitConfig struct {
+	Default   RateLimit `yaml:"default,omitempty"`
+	Uploads   RateLimit `yaml:"uploads,omitempty"`
+	Downloads RateLimit `yaml:"downloads,omitempty"`
+	Status    RateLimit `yaml:"status,omitempty"`
+	Imports   RateLimit `yaml:"imports,omitempty"`
+}
+
+type rateLimitClass struct {
+	RateLimit
+
+	Class string
+}
+
+func (config RateLimitConfig) getClasses() []rateLimitClass {
+	return []rateLimitClass{
+		{config.Default, endpointClassDefault},
+		{config.Uploads, endpointClassUploads},
+		{config.Downloads, endpointClassDownloads},
+		{config.Status, endpointClassStatus},
+		{config.Imports, endpointClassImports},
+	}
+}
+
+// RateLimit is specified in requests per second; negative rate disables
+// limiting.
+type RateLimit struct {
+	Rate  float64 `yaml:"rate,omitempty"`
+	Burst int     `yaml:"burst,omitempty"`
+}
+
+type RetryConfig struct {
+	Attempts *int          `yaml:"attempts,omitempty"`
+	MinDelay time.Duration `yaml:"min_delay,omitempty"`
+	MaxDelay time.Duration `yaml:"max_delay,omitempty"`
 }
 
 func NewConfig(path string) (Config, error) {
@@ -49,7 +139,23 @@ func NewConfig(path string) (Config, error) {
 		path: path,
 	}
 
-	err := ko.Load(path, &config, yaml.Unmarshal)
+	err := ko.Load(
+		path,
+		&config,
+		func(data []byte, resource interface{}) error {
+			document, err := expandConfigVariables(path, data)
+			if err != nil {
+				return err
+			}
+
+			// empty document
+			if document.Kind == 0 {
+				return nil
+			}
+
+			return document.Decode(resource)
+		},
+	)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return config, nil
@@ -58,28 +164,243 @@ func NewConfig(path string) (Config, error) {
 		return config, err
 	}
 
-	if (config.Locales != nil && len(config.Locales) > 0) {
-		config.AppLocaleToLocaleMap = make(map[string]string)
-		config.LocaleToAppLocaleMap = make(map[string]string)
-		for _, locale := range config.Locales {
-			config.AppLocaleToLocaleMap[locale.Application] = locale.Smartling
-			config.LocaleToAppLocaleMap[locale.Smartling] = locale.Application
+	config.buildLocaleMaps()
+
+	for _, value := range config.getValues() {
+		if value.Value != "" {
+			config.setOrigin(value.Key, "config file")
 		}
 	}
 
 	return config, nil
 }
 
+func (config *Config) setOrigin(key string, origin string) {
+	if config.origins == nil {
+		config.origins = map[string]string{}
+	}
+
+	config.origins[getOriginKey(key)] = origin
+}
+
+// Origin returns description of where value of given key came from.
+func (config *Config) Origin(key string) string {
+	if origin, ok := config.origins[getOriginKey(key)]; ok {
+		return origin
+	}
+
+	return "default"
+}
+
+// getOriginKey returns key without list index, because lists are replaced
+// as a whole.
+func getOriginKey(key string) string {
+	return strings.SplitN(key, "[", 2)[0]
+}
+
+type configValue struct {
+	Key   string
+	Value string
+}
+
+// getValues returns all non-file config values, which are set, in stable
+// order.
+func (config *Config) getValues() []configValue {
+	values := []configValue{
+		{"user_id", config.UserID},
+		{"secret", config.Secret},
+		{"secret_file", config.SecretFile},
+		{"secret_command", config.SecretCommand},
+		{"secret_env", config.SecretEnv},
+		{"account_id", config.AccountID},
+		{"project_id", config.ProjectID},
+		{"proxy", config.Proxy},
+		{"smartling_url", config.SmartlingURL},
+	}
+
+	if config.Threads != 0 {
+		values = append(values, configValue{
+			"threads", fmt.Sprint(config.Threads),
+		})
+	}
+
+	if config.Retry.Attempts != nil {
+		values = append(values, configValue{
+			"retry.attempts", fmt.Sprint(*config.Retry.Attempts),
+		})
+	}
+
+	if config.Retry.MinDelay != 0 {
+		values = append(values, configValue{
+			"retry.min_delay", config.Retry.MinDelay.String(),
+		})
+	}
+
+	if config.Retry.MaxDelay != 0 {
+		values = append(values, configValue{
+			"retry.max_delay", config.Retry.MaxDelay.String(),
+		})
+	}
+
+	for _, limit := range config.RateLimit.getClasses() {
+		if limit.Rate != 0 {
+			values = append(values, configValue{
+				"rate_limit." + limit.Class + ".rate", fmt.Sprint(limit.Rate),
+			})
+		}
+
+		if limit.Burst != 0 {
+			values = append(values, configValue{
+				"rate_limit." + limit.Class + ".burst", fmt.Sprint(limit.Burst),
+			})
+		}
+	}
+
+	for i, locale := range config.Locales {
+		values = append(values, configValue{
+			fmt.Sprintf("locales[%d]", i),
+			locale.Application + " = " + locale.Smartling,
+		})
+	}
+
+	return values
+}
+
+func (config *Config) buildLocaleMaps() {
+	config.AppLocaleToLocaleMap = nil
+	config.LocaleToAppLocaleMap = nil
+
+	if len(config.Locales) == 0 {
+		return
+	}
+
+	config.AppLocaleToLocaleMap = make(map[string]string)
+	config.LocaleToAppLocaleMap = make(map[string]string)
+
+	for _, locale := range config.Locales {
+		config.AppLocaleToLocaleMap[locale.Application] = locale.Smartling
+		config.LocaleToAppLocaleMap[locale.Smartling] = locale.Application
+	}
+}
+
+// UseProfile applies values from named profile on top of base config.
+func (config *Config) UseProfile(name string) error {
+	profile, ok := config.Profiles[name]
+	if !ok {
+		var names []string
+		for name := range config.Profiles {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		available := "none"
+		if len(names) > 0 {
+			available = strings.Join(names, ", ")
+		}
+
+		return NewError(
+			fmt.Errorf(`profile "%s" is not found in config`, name),
+
+			`Check "profiles" section of config file %q, available `+
+				`profiles: %s.`,
+			config.path,
+			available,
+		)
+	}
+
+	// secret from profile replaces any secret source of base config
+	if profile.Secret != "" || profile.SecretFile != "" ||
+		profile.SecretCommand != "" || profile.SecretEnv != "" {
+		config.Secret = profile.Secret
+		config.SecretFile = profile.SecretFile
+		config.SecretCommand = profile.SecretCommand
+		config.SecretEnv = profile.SecretEnv
+
+		for _, key := range []string{
+			"secret", "secret_file", "secret_command", "secret_env",
+		} {
+			config.setOrigin(key, fmt.Sprintf("profile %q", name))
+		}
+	}
+
+	overrides := []struct {
+		key    string
+		value  string
+		target *string
+	}{
+		{"user_id", profile.UserID, &config.UserID},
+		{"account_id", profile.AccountID, &config.AccountID},
+		{"project_id", profile.ProjectID, &config.ProjectID},
+		{"proxy", profile.Proxy, &config.Proxy},
+		{"smartling_url", profile.SmartlingURL, &config.SmartlingURL},
+	}
+
+	origin := fmt.Sprintf("profile %q", name)
+
+	for _, override := range overrides {
+		if override.value != "" {
+			*override.target = override.value
+			config.setOrigin(override.key, origin)
+		}
+	}
+
+	if profile.Threads > 0 {
+		config.Threads = profile.Threads
+		config.setOrigin("threads", origin)
+	}
+
+	if len(profile.Locales) > 0 {
+		config.Locales = profile.Locales
+		config.buildLocaleMaps()
+		config.setOrigin("locales", origin)
+	}
+
+	config.Profile = name
+
+	return nil
+}
+
+// GetFileConfig returns config for file with given path or URI. All matching
+// sections of files config are merged from least to most specific one, so
+// values from more specific sections take precedence.
 func (config *Config) GetFileConfig(path string) (FileConfig, error) {
-	var (
-		match FileConfig
-		found bool
-	)
+	sections, err := config.getFileSections(path)
+	if err != nil {
+		return FileConfig{}, err
+	}
+
+	var result FileConfig
+
+	for _, key := range sections {
+		mergeFileConfig(&result, config.Files[key])
+	}
 
-	for key, candidate := range config.Files {
+	if len(sections) > 0 {
+		logger.Debugf(
+			"config sections applied to %q (least to most specific): %q",
+			path,
+			sections,
+		)
+	}
+
+	return result, nil
+}
+
+// getFileSections returns keys of files config sections, which match given
+// path, ordered from least to most specific. Default section, if present,
+// always goes first.
+func (config *Config) getFileSections(path string) ([]string, error) {
+	var sections []string
+
+	if _, ok := config.Files[defaultFileSection]; ok {
+		sections = append(sections, defaultFileSection)
+	}
+
+	for _, key := range config.getFilePatterns() {
 		pattern, err := glob.Compile(key, '/')
 		if err != nil {
-			return FileConfig{}, NewError(
+			return nil, NewError(
 				hierr.Errorf(
 					err,
 					`unable to compile pattern from config file (key "%s")`,
@@ -91,25 +412,189 @@ func (config *Config) GetFileConfig(path string) (FileConfig, error) {
 			)
 		}
 
-		if pattern.Match(path) {
-			match = candidate
-			found = true
+		if pattern.Match(path) || config.matchFilePatternLocally(key, path) {
+			sections = append(sections, key)
 		}
 	}
 
-	defaults := config.Files["default"]
+	return sections, nil
+}
 
-	if !found {
-		return defaults, nil
+// matchFilePatternLocally matches absolute local path against pattern, which
+// starts with / and is resolved against directory with config file, same
+// way as push does, see getConfigFilePattern.
+func (config *Config) matchFilePatternLocally(key string, path string) bool {
+	if !strings.HasPrefix(key, "/") || !filepath.IsAbs(path) ||
+		config.path == "" {
+		return false
 	}
 
-	err := mergo.Merge(&match, defaults)
+	base, err := filepath.Abs(filepath.Dir(config.path))
 	if err != nil {
-		return FileConfig{}, NewError(
-			hierr.Errorf(err, "unable to merge file config options"),
-			`It's internal error. Consider reporting bug.`,
+		return false
+	}
+
+	local := getConfigFilePattern(base, key)
+	if local == key {
+		return false
+	}
+
+	pattern, err := glob.Compile(local, '/')
+	if err != nil {
+		return false
+	}
+
+	return pattern.Match(filepath.ToSlash(path))
+}
+
+// getFilePatterns returns patterns from files config, except default
+// section, ordered from least to most specific:
+//
+// > pattern with more literal (non-wildcard) characters is more specific;
+// > if equal, pattern with less ** wildcards is more specific;
+// > if equal, pattern with less other wildcards is more specific;
+// > otherwise patterns are ordered alphabetically.
+func (config *Config) getFilePatterns() []string {
+	var patterns []string
+
+	for key := range config.Files {
+		if key != defaultFileSection {
+			patterns = append(patterns, key)
+		}
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		a := getPatternSpecificity(patterns[i])
+		b := getPatternSpecificity(patterns[j])
+
+		switch {
+		case a.literals != b.literals:
+			return a.literals < b.literals
+
+		case a.superWildcards != b.superWildcards:
+			return a.superWildcards > b.superWildcards
+
+		case a.wildcards != b.wildcards:
+			return a.wildcards > b.wildcards
+		}
+
+		return patterns[i] < patterns[j]
+	})
+
+	return patterns
+}
+
+type patternSpecificity struct {
+	literals       int
+	superWildcards int
+	wildcards      int
+}
+
+func getPatternSpecificity(pattern string) patternSpecificity {
+	var (
+		specificity patternSpecificity
+		runes       = []rune(pattern)
+	)
+
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+			specificity.literals++
+
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				i++
+				specificity.superWildcards++
+			} else {
+				specificity.wildcards++
+			}
+
+		case '?':
+			specificity.wildcards++
+
+		case '[', '{':
+			closing := ']'
+			if runes[i] == '{' {
+				closing = '}'
+			}
+
+			for i < len(runes) && runes[i] != closing {
+				i++
+			}
+
+			specificity.wildcards++
+
+		default:
+			specificity.literals++
+		}
+	}
+
+	return specificity
+}
+
+// mergeFileConfig sets values from source, which are not empty, into target.
+func mergeFileConfig(target *FileConfig, source FileConfig) {
+	if source.Pull.Format != "" {
+		target.Pull.Format = source.Pull.Format
+	}
+
+	if source.Push.Type != "" {
+		target.Push.Type = source.Push.Type
+	}
+
+	if len(source.Push.Directives) > 0 {
+		directives := map[string]string{}
+
+		for key, value := range target.Push.Directives {
+			directives[key] = value
+		}
+
+		for key, value := range source.Push.Directives {
+			directives[key] = value
+		}
+
+		target.Push.Directives = directives
+	}
+
+	if len(source.Locales) > 0 {
+		// more specific mappings go last, so they take precedence
+		target.Locales = append(
+			append([]LocaleConfig{}, target.Locales...),
+			source.Locales...,
 		)
 	}
+}
+
+// GetAppLocale returns application locale for given Smartling locale. Locales
+// mapping from file config takes precedence over global one. If locale is
+// not mapped, Smartling locale is returned as is.
+func (config *Config) GetAppLocale(file FileConfig, locale string) string {
+	for i := len(file.Locales) - 1; i >= 0; i-- {
+		if file.Locales[i].Smartling == locale {
+			return file.Locales[i].Application
+		}
+	}
+
+	if application, ok := config.LocaleToAppLocaleMap[locale]; ok {
+		return application
+	}
+
+	return locale
+}
+
+// GetLocale returns Smartling locale for given application locale, using same
+// precedence as GetAppLocale. If locale is not mapped, it is returned as is.
+func (config *Config) GetLocale(file FileConfig, locale string) string {
+	for i := len(file.Locales) - 1; i >= 0; i-- {
+		if file.Locales[i].Application == locale {
+			return file.Locales[i].Smartling
+		}
+	}
+
+	if smartling, ok := config.AppLocaleToLocaleMap[locale]; ok {
+		return smartling
+	}
 
-	return match, nil
+	return locale
 }
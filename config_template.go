@@ -11,6 +11,11 @@ var (
 			strings.TrimSpace(`
 # Config file is optional and all configuration options can be set from command
 # line interface.
+#
+# Any value can reference environment variables as ${VAR} or ${VAR:-default};
+# default is used when variable is unset or empty. Every $$ is replaced with
+# literal $, so $${HOME} is kept as ${HOME}, e.g. to be expanded by another
+# tool, and $$$$ is needed to get $$.
 
 # (required) Smartling API V2.0 User Identifier used for authentication.
 #
@@ -22,9 +27,23 @@ user_id:
 # (required) Smartling API V2.0 Token Secret used for authentication.
 #
 # Must be set either in config file or be passed via command line arguments.
+# To keep secret out of config file, it can be read from external source:
+# > secret_file — file with secret, relative to config file;
+# > secret_command — command, which prints secret to stdout;
+# > secret_env — environment variable with secret.
+{% if .SecretFile -%}
+secret_file:
+    "{% .SecretFile %}"
+{% else if .SecretCommand -%}
+secret_command:
+    {% printf "%q" .SecretCommand %}
+{% else if .SecretEnv -%}
+secret_env:
+    "{% .SecretEnv %}"
+{% else -%}
 secret:
     "{% .Secret %}"
-
+{% end %}
 
 # (optional) Account ID used for projects list requests.
 account_id:
@@ -43,10 +62,55 @@ project_id:
 #proxy:
 #    "PROXY_URL"
 
+# (optional) Base Smartling API URL.
+#smartling_url:
+#    "https://api.smartling.com"
+
+# (optional) Named profiles, which override base values when selected by
+# --profile option or SMARTLING_PROFILE environment variable. Profile can
+# set user_id, secret, account_id, project_id, proxy, smartling_url, threads
+# and locales.
+#profiles:
+#    staging:
+#        project_id: "STAGING_PROJECT_ID"
+#    production:
+#        project_id: "PRODUCTION_PROJECT_ID"
+#        threads: 8
+
+# (optional) Failed API requests (network errors, 429 and 5xx responses) are
+# retried with exponential backoff. Number of attempts can be overridden by
+# --retries option.
+#retry:
+#    attempts: 3
+#    min_delay: 1s
+#    max_delay: 30s
+
+# (optional) Requests to API are throttled separately for uploads, downloads,
+# status, imports and all other (default) requests. Rate is specified in
+# requests per second, negative rate disables throttling. When API reports
+# that rate limit is exceeded, number of concurrent requests is reduced.
+#rate_limit:
+#    default:
+#        rate: 10
+#        burst: 10
+#    uploads:
+#        rate: 5
+#        burst: 5
+#    downloads:
+#        rate: 10
+#        burst: 10
+#    status:
+#        rate: 10
+#        burst: 10
+#    imports:
+#        rate: 0.66
+#        burst: 1
+
 # (optional) Additional file-specific settings for push and pull commands.
 files:
-    # (optional) Special default section will apply configuration to all file
-    # types except files, which URIs match following rules.
+    # (optional) Special default section will apply configuration to all
+    # files. Settings from sections, which match file URI, are applied on top
+    # of it.
     default:
         # (optional) Defines pull-specific options.
         pull:
@@ -64,7 +128,13 @@ files:
     #
     # Note, that pattern should start either with /, * or ** to be matched
     # in case when file was pushed with leading /. Checkout files list in
-    # your project first.
+    # your project first. When push searches for local files, such patterns
+    # are resolved against directory with this file.
+    #
+    # If several patterns match file URI, settings are merged from least to
+    # most specific pattern: pattern with more literal (non-wildcard)
+    # characters wins. Run "smartling-cli config show <uri>" to check
+    # effective settings.
     "/path/to/*.properties":
         # (optional) Defines push-specific options.
         push:
@@ -83,6 +153,12 @@ files:
         pull:
             format: "{{name .FileURI}}{{with .Locale}}_{{.}}{{end}}{{ext .FileURI}}"
 
+        # (optional) Locales mapping for matching files, which is merged over
+        # global locales mapping, so .AppLocale can differ between files.
+        #locales:
+        #    - application: "zh-rTW"
+        #      smartling: "zh-TW"
+
 # vim: ft=yaml
 `)))
 )
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigUseProfile(t *testing.T) {
+	config := Config{
+		UserID:    "user",
+		ProjectID: "base",
+		Threads:   4,
+		Locales: []LocaleConfig{
+			{Application: "de", Smartling: "de-DE"},
+		},
+		Profiles: map[string]ProfileConfig{
+			"production": {
+				ProjectID:    "production",
+				SmartlingURL: "https://api.example.com",
+				Locales: []LocaleConfig{
+					{Application: "fr", Smartling: "fr-FR"},
+				},
+			},
+		},
+	}
+
+	config.buildLocaleMaps()
+
+	assert.NoError(t, config.UseProfile("production"))
+	assert.Equal(t, "user", config.UserID)
+	assert.Equal(t, "production", config.ProjectID)
+	assert.Equal(t, "https://api.example.com", config.SmartlingURL)
+	assert.Equal(t, 4, config.Threads)
+	assert.Equal(t, map[string]string{"fr-FR": "fr"}, config.LocaleToAppLocaleMap)
+
+	assert.Equal(t, `profile "production"`, config.Origin("project_id"))
+	assert.Equal(t, `profile "production"`, config.Origin("locales[0]"))
+	assert.Equal(t, "default", config.Origin("proxy"))
+
+	assert.Error(t, config.UseProfile("staging"))
+}
+
+func TestResolveSecret(t *testing.T) {
+	dir := t.TempDir()
+
+	err := ioutil.WriteFile(
+		filepath.Join(dir, ".smartling.secret"),
+		[]byte("from-file\n"),
+		0600,
+	)
+	assert.NoError(t, err)
+
+	config := Config{
+		SecretFile: ".smartling.secret",
+		path:       filepath.Join(dir, "smartling.yml"),
+	}
+
+	assert.NoError(t, resolveSecret(&config))
+	assert.Equal(t, "from-file", config.Secret)
+
+	config = Config{SecretCommand: "echo from-command"}
+
+	assert.NoError(t, resolveSecret(&config))
+	assert.Equal(t, "from-command", config.Secret)
+
+	config = Config{SecretEnv: "TEST_SMARTLING_UNSET_SECRET"}
+
+	assert.Error(t, resolveSecret(&config))
+}
+
+func TestConfigGetFileConfig(t *testing.T) {
+	var any, src, messages, defaults FileConfig
+
+	defaults.Pull.Format = "default"
+
+	any.Push.Type = "json"
+	any.Push.Directives = map[string]string{"a": "any", "b": "any"}
+
+	src.Pull.Format = "src"
+	src.Push.Directives = map[string]string{"b": "src"}
+
+	messages.Pull.Format = "messages"
+
+	config := Config{
+		Files: map[string]FileConfig{
+			"default":              defaults,
+			"**":                   any,
+			"/src/**.json":         src,
+			"/src/*/messages.json": messages,
+		},
+	}
+
+	assert.Equal(
+		t,
+		[]string{"**", "/src/**.json", "/src/*/messages.json"},
+		config.getFilePatterns(),
+	)
+
+	for i := 0; i < 10; i++ {
+		result, err := config.GetFileConfig("/src/en/messages.json")
+		assert.NoError(t, err)
+		assert.Equal(t, "messages", result.Pull.Format)
+		assert.Equal(t, "json", result.Push.Type)
+		assert.Equal(
+			t,
+			map[string]string{"a": "any", "b": "src"},
+			result.Push.Directives,
+		)
+	}
+
+	result, err := config.GetFileConfig("/docs/readme.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "default", result.Pull.Format)
+
+	// sections must not be modified while merging
+	assert.Equal(
+		t,
+		map[string]string{"a": "any", "b": "any"},
+		config.Files["**"].Push.Directives,
+	)
+}
+
+func TestConfigGetAppLocale(t *testing.T) {
+	var android, web FileConfig
+
+	android.Locales = []LocaleConfig{
+		{Application: "zh-rTW", Smartling: "zh-TW"},
+	}
+
+	web.Locales = []LocaleConfig{
+		{Application: "zh-Hant", Smartling: "zh-TW"},
+	}
+
+	config := Config{
+		Locales: []LocaleConfig{
+			{Application: "zh_TW", Smartling: "zh-TW"},
+			{Application: "de", Smartling: "de-DE"},
+		},
+		Files: map[string]FileConfig{
+			"**/res/**.xml":  android,
+			"**/web/**.json": web,
+		},
+	}
+
+	config.buildLocaleMaps()
+
+	for path, expected := range map[string]string{
+		"/app/res/values/strings.xml": "zh-rTW",
+		"/app/web/messages.json":      "zh-Hant",
+		"/app/other.txt":              "zh_TW",
+	} {
+		file, err := config.GetFileConfig(path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, config.GetAppLocale(file, "zh-TW"), path)
+		assert.Equal(t, "de", config.GetAppLocale(file, "de-DE"), path)
+		assert.Equal(t, "fr-FR", config.GetAppLocale(file, "fr-FR"), path)
+	}
+}
+
+func TestLoadConfigSecretSourceFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "smartling.yml")
+
+	err := ioutil.WriteFile(
+		path,
+		[]byte("user_id: user\nsecret_env: TEST_SMARTLING_UNSET_SECRET\n"),
+		0600,
+	)
+	assert.NoError(t, err)
+
+	args := func(command string) map[string]interface{} {
+		return map[string]interface{}{
+			"--config":  path,
+			"--threads": "4",
+			"init":      false,
+			"config":    command == "config",
+			"files":     command == "files",
+			"projects":  false,
+			"list":      command == "files",
+		}
+	}
+
+	// config show displays error instead of failing
+	config, err := loadConfig(args("config"))
+	assert.NoError(t, err)
+	assert.Error(t, config.secretError)
+
+	_, err = loadConfig(args("files"))
+	assert.Equal(t, config.secretError, err)
+
+	os.Setenv("SMARTLING_SECRET", "from-env")
+	defer os.Unsetenv("SMARTLING_SECRET")
+
+	os.Setenv("SMARTLING_PROJECT_ID", "project")
+	defer os.Unsetenv("SMARTLING_PROJECT_ID")
+
+	config, err = loadConfig(args("files"))
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", config.Secret)
+}
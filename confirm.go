@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/reconquest/hierr-go"
+	"github.com/tcnksm/go-input"
+)
+
+func confirm(message string) (bool, error) {
+	answer, err := input.DefaultUI().Ask(
+		message+" [y/N]",
+		&input.Options{
+			Default:     "n",
+			HideDefault: true,
+			HideOrder:   true,
+		},
+	)
+	if err != nil {
+		return false, hierr.Errorf(err, "unable to read confirmation")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+func doConfigShow(config Config, args map[string]interface{}) error {
+	var (
+		uri, _ = args["<uri>"].(string)
+	)
+
+	fmt.Printf("# %s\n", config.path)
+
+	if config.Profile != "" {
+		fmt.Printf("# profile %q\n", config.Profile)
+	}
+
+	// output of config show is likely to be pasted into issues and chats,
+	// so sensitive values are hidden regardless of verbosity
+	redactor := NewRedactedLog()
+	redactor.ToggleRedact(true)
+	redactor.HideFromConfig(config)
+
+	table := NewTableWriter(os.Stdout)
+
+	values := append(config.getValues(), getDefaultConfigValues(config)...)
+
+	for _, value := range values {
+		if value.Value == "" {
+			continue
+		}
+
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%s\n",
+			value.Key,
+			redactor.Redact(value.Value),
+			config.Origin(value.Key),
+		)
+	}
+
+	err := RenderTable(table)
+	if err != nil {
+		return err
+	}
+
+	if config.Secret == "" && config.getSecretSource() == "secret_command" {
+		fmt.Printf("\n# secret is read by secret_command, which is not run\n")
+	}
+
+	if config.secretError != nil {
+		cause := config.secretError
+		if err, ok := cause.(Error); ok {
+			cause = err.Cause
+		}
+
+		fmt.Printf(
+			"\n# secret from %s is not available: %s\n",
+			config.getSecretSource(),
+			cause,
+		)
+	}
+
+	if uri == "" {
+		return nil
+	}
+
+	fileConfig, err := config.GetFileConfig(uri)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n# files section for %q\n", uri)
+
+	table = NewTableWriter(os.Stdout)
+
+	for _, value := range getFileConfigValues(fileConfig) {
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%s\n",
+			value.Key,
+			value.Value,
+			getFileConfigOrigin(config, uri, value),
+		)
+	}
+
+	return RenderTable(table)
+}
+
+// getDefaultConfigValues returns values, which are used when they are not
+// set in config.
+func getDefaultConfigValues(config Config) []configValue {
+	var values []configValue
+
+	if config.Retry.Attempts == nil {
+		values = append(values, configValue{
+			"retry.attempts", fmt.Sprint(defaultRetryAttempts),
+		})
+	}
+
+	if config.Retry.MinDelay == 0 {
+		values = append(values, configValue{
+			"retry.min_delay", defaultRetryMinDelay.String(),
+		})
+	}
+
+	if config.Retry.MaxDelay == 0 {
+		values = append(values, configValue{
+			"retry.max_delay", defaultRetryMaxDelay.String(),
+		})
+	}
+
+	for _, class := range config.RateLimit.getClasses() {
+		if class.Rate != 0 {
+			continue
+		}
+
+		limit := defaultRateLimits[class.Class]
+
+		values = append(
+			values,
+			configValue{
+				"rate_limit." + class.Class + ".rate",
+				fmt.Sprintf("%.2f", limit.Rate),
+			},
+			configValue{
+				"rate_limit." + class.Class + ".burst",
+				fmt.Sprint(limit.Burst),
+			},
+		)
+	}
+
+	return values
+}
+
+func getFileConfigValues(config FileConfig) []configValue {
+	values := []configValue{
+		{"pull.format", config.Pull.Format},
+		{"push.type", config.Push.Type},
+	}
+
+	var names []string
+	for name := range config.Push.Directives {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		values = append(values, configValue{
+			"push.directives." + name,
+			config.Push.Directives[name],
+		})
+	}
+
+	// locales are keyed by Smartling locale, because merged mapping may
+	// contain several entries for the same locale
+	locales := map[string]string{}
+	names = nil
+
+	for _, locale := range config.Locales {
+		if _, ok := locales[locale.Smartling]; !ok {
+			names = append(names, locale.Smartling)
+		}
+
+		locales[locale.Smartling] = locale.Application
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		values = append(values, configValue{
+			"locales." + name,
+			locales[name],
+		})
+	}
+
+	return values
+}
+
+// getFileConfigOrigin returns files section which provides specified value
+// for given file URI.
+func getFileConfigOrigin(config Config, uri string, value configValue) string {
+	if value.Value == "" {
+		return "not set"
+	}
+
+	sections, err := config.getFileSections(uri)
+	if err != nil {
+		return "default"
+	}
+
+	// sections are ordered from least to most specific, so the last one,
+	// which provides the value, takes precedence
+	for index := len(sections) - 1; index >= 0; index-- {
+		for _, candidate := range getFileConfigValues(
+			config.Files[sections[index]],
+		) {
+			if candidate == value {
+				return fmt.Sprintf("files %q", sections[index])
+			}
+		}
+	}
+
+	return "default"
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+)
+
+func doConfigValidate(path string) error {
+	issues, err := validateConfig(path)
+	if err != nil {
+		return err
+	}
+
+	var errors int
+
+	for _, issue := range issues {
+		if !issue.Warning {
+			errors++
+		}
+
+		fmt.Printf("%s:%s\n", path, issue)
+	}
+
+	if errors > 0 {
+		return NewError(
+			fmt.Errorf("config file has %d error(s)", errors),
+			`Fix errors listed above and run validation again.`,
+		)
+	}
+
+	fmt.Printf("%s is valid\n", path)
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	smartling "github.com/Smartling/api-sdk-go"
@@ -8,6 +9,7 @@ import (
 )
 
 func doFilesDelete(
+	ctx context.Context,
 	client *smartling.Client,
 	config Config,
 	args map[string]interface{},
@@ -17,10 +19,12 @@ func doFilesDelete(
 		uri     = args["<uri>"].(string)
 	)
 
-	var (
-		err   error
-		files []smartling.File
-	)
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	var files []smartling.File
 
 	if uri == "-" {
 		files, err = readFilesFromStdin()
@@ -43,7 +47,37 @@ func doFilesDelete(
 		)
 	}
 
-	for _, file := range files {
+	if isDryRun(args) {
+		reportDryRun("deleted")
+
+		for _, file := range files {
+			if output.IsStructured() {
+				output.Add(DeleteRecord{
+					FileURI: file.FileURI,
+					Status:  "planned",
+				})
+			} else {
+				fmt.Printf("%s\n", file.FileURI)
+			}
+		}
+
+		return output.Flush()
+	}
+
+	for index, file := range files {
+		if ctx.Err() != nil {
+			for _, file := range files[index:] {
+				logger.Warningf("%s was not deleted", file.FileURI)
+			}
+
+			err := output.Flush()
+			if err != nil {
+				return err
+			}
+
+			return newInterruptedError("deleted", index, len(files))
+		}
+
 		err := client.DeleteFile(project, file.FileURI)
 		if err != nil {
 			return hierr.Errorf(
@@ -53,8 +87,15 @@ func doFilesDelete(
 			)
 		}
 
-		fmt.Printf("%s deleted\n", file.FileURI)
+		if output.IsStructured() {
+			output.Add(DeleteRecord{
+				FileURI: file.FileURI,
+				Status:  "deleted",
+			})
+		} else {
+			fmt.Printf("%s deleted\n", file.FileURI)
+		}
 	}
 
-	return nil
+	return output.Flush()
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	smartling "github.com/Smartling/api-sdk-go"
@@ -22,6 +23,16 @@ func doFilesImport(
 		fileType, _ = args["--type"].(string)
 	)
 
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	importErrors, err := NewImportErrorReport(args)
+	if err != nil {
+		return err
+	}
+
 	contents, err := ioutil.ReadFile(file)
 	if err != nil {
 		return NewError(
@@ -65,6 +76,38 @@ func doFilesImport(
 		}
 	}
 
+	record := ImportRecord{
+		File:     file,
+		FileURI:  uri,
+		Locale:   locale,
+		FileType: string(request.FileType),
+	}
+
+	if isDryRun(args) {
+		reportDryRun("imported")
+
+		if output.IsStructured() {
+			record.Status = "planned"
+			output.Add(record)
+
+			return output.Flush()
+		}
+
+		table := NewTableWriter(os.Stdout)
+
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%s\t%s\t%s\n",
+			file,
+			uri,
+			locale,
+			request.FileType,
+			request.TranslationState,
+		)
+
+		return RenderTable(table)
+	}
+
 	result, err := client.Import(project, locale, request)
 	if err != nil {
 		return hierr.Errorf(
@@ -75,12 +118,31 @@ func doFilesImport(
 		)
 	}
 
-	fmt.Printf(
-		"%s imported [%d strings %d words]\n",
-		file,
-		result.StringCount,
-		result.WordCount,
-	)
+	importErrors.Add(file, uri, locale, result)
+
+	if output.IsStructured() {
+		record.Status = "imported"
+		record.Strings = result.StringCount
+		record.Words = result.WordCount
+		output.Add(record)
+
+		err = output.Flush()
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf(
+			"%s imported [%d strings %d words]\n",
+			file,
+			result.StringCount,
+			result.WordCount,
+		)
+	}
+
+	err = importErrors.Write()
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return importErrors.Err()
 }
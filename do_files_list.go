@@ -20,6 +20,11 @@ func doFilesList(
 		uri, _  = args["<uri>"].(string)
 	)
 
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
 	if args["--format"] == nil {
 		args["--format"] = defaultFilesListFormat
 	}
@@ -34,6 +39,19 @@ func doFilesList(
 		return err
 	}
 
+	if output.IsStructured() {
+		for _, file := range files {
+			output.Add(FileRecord{
+				FileURI:         file.FileURI,
+				FileType:        string(file.FileType),
+				LastUploaded:    file.LastUploaded.Time,
+				HasInstructions: file.HasInstructions,
+			})
+		}
+
+		return output.Flush()
+	}
+
 	table := NewTableWriter(os.Stdout)
 
 	for _, file := range files {
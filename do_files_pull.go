@@ -1,28 +1,47 @@
 package main
 
 import (
+	"context"
+	"os"
+	"sync/atomic"
+
 	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/reconquest/hierr-go"
 )
 
 func doFilesPull(
+	ctx context.Context,
 	client *smartling.Client,
 	config Config,
 	args map[string]interface{},
 ) error {
 	var (
-		branch, useBranch   = args["--branch"].(string)
-		project = config.ProjectID
-		uri, _  = args["<uri>"].(string)
+		branch, useBranch = args["--branch"].(string)
+		project           = config.ProjectID
+		uri, _            = args["<uri>"].(string)
+		failFast, _       = args["--fail-fast"].(bool)
 	)
 
 	// if args["--format"] == nil {
 	// 	args["--format"] = defaultFilePullFormat
 	// }
 
-	var (
-		err   error
-		files []smartling.File
-	)
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	// source locale is required for file name format
+	details, err := client.GetProjectDetails(project)
+	if err != nil {
+		return hierr.Errorf(
+			err,
+			`unable to get project "%s" details`,
+			project,
+		)
+	}
+
+	var files []smartling.File
 
 	if uri == "-" {
 		files, err = readFilesFromStdin()
@@ -39,22 +58,95 @@ func doFilesPull(
 		}
 	}
 
-	pool := NewThreadPool(config.Threads)
+	state, err := loadState(config)
+	if err != nil {
+		return err
+	}
+
+	var (
+		pool    = NewThreadPool(ctx, config.Threads)
+		report  = NewFailureReport("pull", failFast)
+		pulled  int32
+		skipped int32
+	)
+
+	for index, file := range files {
+		if report.Stopped() {
+			atomic.AddInt32(&skipped, int32(len(files)-index))
+
+			break
+		}
 
-	for _, file := range files {
 		// func closure required to pass different file objects to goroutines
 		func(file smartling.File) {
-			pool.Do(func() {
-				err := downloadFileTranslations(client, config, args, file)
+			scheduled := pool.Do(func() {
+				// other task could fail while this one was waiting for
+				// available thread
+				if report.Stopped() {
+					atomic.AddInt32(&skipped, 1)
+					return
+				}
+
+				err := downloadFileTranslations(
+					client,
+					config,
+					args,
+					file,
+					details.SourceLocaleID,
+					state,
+					report,
+					output,
+				)
 
 				if err != nil {
-					logger.Error(err)
+					report.Add(Failure{
+						FileURI: file.FileURI,
+						Error:   err,
+					})
+
+					return
 				}
+
+				atomic.AddInt32(&pulled, 1)
 			})
+
+			if !scheduled {
+				report.Add(Failure{
+					FileURI: file.FileURI,
+					Error:   errInterrupted,
+				})
+			}
 		}(file)
 	}
 
 	pool.Wait()
 
-	return nil
+	if skipped > 0 {
+		logger.Warningf(
+			"%d file(s) were not pulled because of --fail-fast mode",
+			skipped,
+		)
+	}
+
+	// successfully pulled files should be recorded even if some failed
+	err = state.Save()
+	if err != nil {
+		return err
+	}
+
+	err = output.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = report.Render(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return newInterruptedError("pulled", int(pulled), len(files))
+	}
+
+	return report.Err()
 }
@@ -1,36 +1,75 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	smartling "github.com/Smartling/api-sdk-go"
 	"github.com/reconquest/hierr-go"
 )
 
 func doFilesPush(
+	ctx context.Context,
 	client smartling.ClientInterface,
 	config Config,
 	args map[string]interface{},
+) error {
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	err = pushFiles(ctx, client, config, args, output)
+
+	// records collected before failure are still written
+	flushErr := output.Flush()
+	if err != nil {
+		return err
+	}
+
+	return flushErr
+}
+
+func pushFiles(
+	ctx context.Context,
+	client smartling.ClientInterface,
+	config Config,
+	args map[string]interface{},
+	output *Output,
 ) error {
 	var (
-		failedFiles   []string
-		project       = config.ProjectID
-		result        error
-		file, _       = args["<file>"].(string)
-		uri, useURI   = args["<uri>"].(string)
+		failedFiles       []string
+		project           = config.ProjectID
+		result            error
+		file, _           = args["<file>"].(string)
+		uri, useURI       = args["<uri>"].(string)
 		branch, useBranch = args["--branch"].(string)
-		locales, _    = args["--locale"].([]string)
-		authorize     = args["--authorize"].(bool)
-		directory     = args["--directory"].(string)
-		fileType, _   = args["--type"].(string)
-		directives, _ = args["--directive"].([]string)
+		locales, _        = args["--locale"].([]string)
+		authorize         = args["--authorize"].(bool)
+		directory         = args["--directory"].(string)
+		fileType, _       = args["--type"].(string)
+		directives, _     = args["--directive"].([]string)
+		force, _          = args["--force"].(bool)
+		prune, _          = args["--prune"].(bool)
+		excludes, _       = args["--prune-exclude"].([]string)
+		confirmed, _      = args["--yes"].(bool)
 	)
 
+	if prune && useURI {
+		return NewError(
+			errors.New(`--prune can't be used together with <uri>`),
+
+			`Remove <uri> argument to prune remote files, which were pushed`+
+				` with local paths as URIs.`,
+		)
+	}
+
 	if branch == "@auto" {
 		var err error
 
@@ -49,14 +88,31 @@ func doFilesPush(
 		branch = strings.TrimSuffix(branch, "/") + "/"
 	}
 
+	base, err := filepath.Abs(config.path)
+	if err != nil {
+		return NewError(
+			hierr.Errorf(
+				err,
+				`unable to resolve absolute path to config`,
+			),
+
+			`It's internal error, please, contact developer for more info`,
+		)
+	}
+
+	base = filepath.Dir(base)
+
 	patterns := []string{}
 
 	if file != "" {
 		patterns = append(patterns, file)
 	} else {
-		for pattern, section := range config.Files {
-			if section.Push.Type != "" {
-				patterns = append(patterns, pattern)
+		for _, pattern := range config.getFilePatterns() {
+			if config.Files[pattern].Push.Type != "" {
+				patterns = append(
+					patterns,
+					getConfigFilePattern(base, pattern),
+				)
 			}
 		}
 	}
@@ -106,19 +162,19 @@ func doFilesPush(
 		)
 	}
 
-	base, err := filepath.Abs(config.path)
+	state, err := loadState(config)
 	if err != nil {
-		return NewError(
-			hierr.Errorf(
-				err,
-				`unable to resolve absolute path to config`,
-			),
-
-			`It's internal error, please, contact developer for more info`,
-		)
+		return err
 	}
 
-	base = filepath.Dir(base)
+	var (
+		items []pushItem
+
+		// local contains URIs of all matched local files, including skipped
+		// ones, so they will not be pruned
+		local = map[string]bool{}
+	)
+
 	dset := map[string]bool{}
 	for _, file := range files {
 		if _, ok := dset[file]; ok {
@@ -194,6 +250,8 @@ func doFilesPush(
 				`Check that file exists and readable by current user.`,
 			)
 		}
+		local[branch+uri] = true
+
 		if len(contents) == 0 {
 			fmt.Fprintf(os.Stderr, "%s skipped empty file.", file)
 			continue
@@ -230,7 +288,12 @@ func doFilesPush(
 			request.FileType = smartling.FileType(fileConfig.Push.Type)
 		}
 
-		request.Smartling.Directives = fileConfig.Push.Directives
+		// directives from config are shared between files, so they should
+		// be copied before file-specific directives are added
+		request.Smartling.Directives = map[string]string{}
+		for name, value := range fileConfig.Push.Directives {
+			request.Smartling.Directives[name] = value
+		}
 
 		for _, directive := range directives {
 			spec := strings.SplitN(directive, "=", 2)
@@ -245,63 +308,380 @@ func doFilesPush(
 				)
 			}
 
-			if request.Smartling.Directives == nil {
-				request.Smartling.Directives = map[string]string{}
-			}
-
 			request.Smartling.Directives[spec[0]] = spec[1]
 		}
 
 		if _, ok := request.Smartling.Directives["namespace"]; ok == false && useBranch {
-			if request.Smartling.Directives == nil {
-				request.Smartling.Directives = map[string]string{}
-			}
-
 			fileName := filepath.Base(file)
 			fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
 			request.Smartling.Directives["namespace"] = fileName
 		}
 
 		logger.Debugf("namespace: %s\n", request.Smartling.Directives["namespace"])
-		response, err := client.UploadFile(project, request)
 
-		if err != nil {
-			if returnError(err) {
-				return NewError(
-					err,
-					fmt.Sprintf(`unable to upload file "%s"`, file),
-					`Check, that you have enough permissions to upload file to`+
-						` the specified project`,
+		pushed := PushStateEntry{
+			Hash:               hashContents(contents),
+			Type:               string(request.FileType),
+			Directives:         request.Smartling.Directives,
+			Authorize:          request.Authorize,
+			LocalesToAuthorize: request.LocalesToAuthorize,
+		}
+
+		if !force && state.IsPushed(project, request.FileURI, pushed) {
+			logger.Infof("%s is not changed since last push, skipping", file)
+
+			if output.IsStructured() {
+				output.Add(PushRecord{
+					File:       file,
+					FileURI:    request.FileURI,
+					FileType:   string(request.FileType),
+					Namespace:  request.Smartling.Directives["namespace"],
+					Directives: request.Smartling.Directives,
+					Status:     "unchanged",
+				})
+			} else {
+				fmt.Printf(
+					"%s (namespace:%s type:%s) unchanged\n",
+					uri,
+					request.Smartling.Directives["namespace"],
+					request.FileType,
 				)
 			}
-			fmt.Fprintf(os.Stderr,"%+v\n",err)
-			_, _ = fmt.Fprintln(os.Stderr, "Unable to upload file "+file)
-			failedFiles = append(failedFiles, file)
-		} else {
-			status := "new"
-			if response.Overwritten {
-				status = "overwritten"
+
+			continue
+		}
+
+		items = append(items, pushItem{
+			File:    file,
+			URI:     uri,
+			Request: request,
+			State:   pushed,
+		})
+	}
+
+	var scope pruneScope
+
+	if prune {
+		var scopePatterns []string
+
+		for _, pattern := range patterns {
+			scopePattern, err := getPruneScopePattern(base, directory, pattern)
+			if err != nil {
+				return err
+			}
+
+			scopePatterns = append(scopePatterns, scopePattern)
+		}
+
+		scope, err = newPruneScope(branch, scopePatterns, excludes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if isDryRun(args) {
+		err = printPushPlan(items, output)
+		if err != nil {
+			return err
+		}
+
+		if prune {
+			candidates, err := getPruneCandidates(client, project, scope, local)
+			if err != nil {
+				return err
 			}
 
-			fmt.Printf(
-				"%s (namespace:%s type:%s) %s [%d strings %d words]\n",
-				uri,
-				request.Smartling.Directives["namespace"],
-				request.FileType,
-				status,
-				response.StringCount,
-				response.WordCount,
+			return pruneRemoteFiles(
+				client,
+				project,
+				state,
+				candidates,
+				output,
+				true,
+				false,
 			)
 		}
+
+		return nil
+	}
+
+	var (
+		results = make([]pushResult, len(items))
+		printed = 0
+
+		// fatal is set when error, which prevents any further uploads, occurs
+		fatal error
+
+		mutex sync.Mutex
+		pool  = NewThreadPool(ctx, config.Threads)
+	)
+
+	// results are printed in the same order files were matched, as soon as
+	// all preceding uploads are done
+	flush := func() {
+		for ; printed < len(results) && results[printed].Done; printed++ {
+			printPushResult(items[printed], results[printed], output)
+		}
+	}
+
+	for index, item := range items {
+		mutex.Lock()
+		stop := fatal != nil
+		mutex.Unlock()
+
+		if stop {
+			break
+		}
+
+		// func closure required to pass different items to goroutines
+		func(index int, item pushItem) {
+			pool.Do(func() {
+				mutex.Lock()
+				stop := fatal != nil
+				mutex.Unlock()
+
+				if stop {
+					return
+				}
+
+				response, err := client.UploadFile(project, item.Request)
+
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				if err != nil && returnError(err) {
+					if fatal == nil {
+						fatal = NewError(
+							err,
+							fmt.Sprintf(`unable to upload file "%s"`, item.File),
+							`Check, that you have enough permissions to upload file to`+
+								` the specified project`,
+						)
+					}
+
+					return
+				}
+
+				results[index] = pushResult{
+					Done:     true,
+					Response: response,
+					Error:    err,
+				}
+
+				if err == nil {
+					state.SetPushed(project, item.Request.FileURI, item.State)
+				}
+
+				flush()
+			})
+		}(index, item)
+	}
+
+	pool.Wait()
+
+	var uploaded int
+
+	for index, result := range results {
+		if !result.Done {
+			if ctx.Err() != nil && fatal == nil {
+				printPushInterrupted(items[index], output)
+			}
+
+			continue
+		}
+
+		// remaining results are left unprinted only if push was stopped
+		if index >= printed {
+			printPushResult(items[index], result, output)
+		}
+
+		if result.Error != nil {
+			failedFiles = append(failedFiles, items[index].File)
+		} else {
+			uploaded++
+		}
+	}
+
+	// files uploaded so far should not be uploaded again, even if push was
+	// interrupted by fatal error
+	err = state.Save()
+	if err != nil {
+		return err
+	}
+
+	if fatal != nil {
+		return fatal
+	}
+
+	if ctx.Err() != nil {
+		return newInterruptedError("uploaded", uploaded, len(items))
 	}
 
 	if len(failedFiles) != 0 {
 		result = NewError(fmt.Errorf("failed to upload %d files", len(failedFiles)), "failed to upload files "+strings.Join(failedFiles, ", "))
 	}
 
+	if prune {
+		if result != nil {
+			logger.Warningf("not pruning remote files because some uploads failed")
+
+			return result
+		}
+
+		candidates, err := getPruneCandidates(client, project, scope, local)
+		if err != nil {
+			return err
+		}
+
+		return pruneRemoteFiles(
+			client,
+			project,
+			state,
+			candidates,
+			output,
+			false,
+			confirmed,
+		)
+	}
+
 	return result
 }
 
+type pushItem struct {
+	File    string
+	URI     string
+	Request smartling.FileUploadRequest
+	State   PushStateEntry
+}
+
+type pushResult struct {
+	Done     bool
+	Response *smartling.FileUploadResult
+	Error    error
+}
+
+func printPushPlan(items []pushItem, output *Output) error {
+	reportDryRun("uploaded")
+
+	if output.IsStructured() {
+		for _, item := range items {
+			record := item.Record()
+			record.Status = "planned"
+
+			output.Add(record)
+		}
+
+		return nil
+	}
+
+	table := NewTableWriter(os.Stdout)
+
+	for _, item := range items {
+		authorize := "-"
+
+		switch {
+		case item.Request.Authorize:
+			authorize = "all"
+
+		case len(item.Request.LocalesToAuthorize) > 0:
+			authorize = strings.Join(item.Request.LocalesToAuthorize, ",")
+		}
+
+		namespace := item.Request.Smartling.Directives["namespace"]
+		if namespace == "" {
+			namespace = "-"
+		}
+
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%s\t%s\t%s\n",
+			item.Request.FileURI,
+			item.Request.FileType,
+			formatDirectives(item.Request.Smartling.Directives),
+			namespace,
+			authorize,
+		)
+	}
+
+	return RenderTable(table)
+}
+
+// Record returns push output record filled with request details.
+func (item pushItem) Record() PushRecord {
+	record := PushRecord{
+		File:       item.File,
+		FileURI:    item.Request.FileURI,
+		FileType:   string(item.Request.FileType),
+		Namespace:  item.Request.Smartling.Directives["namespace"],
+		Directives: item.Request.Smartling.Directives,
+		Authorize:  item.Request.LocalesToAuthorize,
+	}
+
+	if item.Request.Authorize {
+		record.Authorize = []string{"all"}
+	}
+
+	return record
+}
+
+func printPushInterrupted(item pushItem, output *Output) {
+	if output.IsStructured() {
+		record := item.Record()
+		record.Status = "interrupted"
+
+		output.Add(record)
+
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "%s was not uploaded\n", item.File)
+}
+
+func printPushResult(item pushItem, result pushResult, output *Output) {
+	if output.IsStructured() {
+		record := item.Record()
+
+		if result.Error != nil {
+			record.Status = "failed"
+			record.Error = compactError(result.Error)
+		} else {
+			record.Status = "new"
+			if result.Response.Overwritten {
+				record.Status = "overwritten"
+			}
+
+			record.Strings = result.Response.StringCount
+			record.Words = result.Response.WordCount
+		}
+
+		output.Add(record)
+
+		return
+	}
+
+	if result.Error != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", result.Error)
+		_, _ = fmt.Fprintln(os.Stderr, "Unable to upload file "+item.File)
+
+		return
+	}
+
+	status := "new"
+	if result.Response.Overwritten {
+		status = "overwritten"
+	}
+
+	fmt.Printf(
+		"%s (namespace:%s type:%s) %s [%d strings %d words]\n",
+		item.URI,
+		item.Request.Smartling.Directives["namespace"],
+		item.Request.FileType,
+		status,
+		result.Response.StringCount,
+		result.Response.WordCount,
+	)
+}
+
 func returnError(err error) bool {
 	if errors.Is(err, smartling.NotAuthorizedError{}) {
 		return true
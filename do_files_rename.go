@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	smartling "github.com/Smartling/api-sdk-go"
 	"github.com/reconquest/hierr-go"
 )
@@ -16,6 +18,14 @@ func doFilesRename(
 		newURI  = args["<new-uri>"].(string)
 	)
 
+	if isDryRun(args) {
+		reportDryRun("renamed")
+
+		fmt.Printf("%s -> %s\n", oldURI, newURI)
+
+		return nil
+	}
+
 	err := client.RenameFile(project, oldURI, newURI)
 	if err != nil {
 		return hierr.Errorf(
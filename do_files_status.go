@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 )
 
 func doFilesStatus(
+	ctx context.Context,
 	client *smartling.Client,
 	config Config,
 	args map[string]interface{},
@@ -20,8 +22,19 @@ func doFilesStatus(
 		directory = args["--directory"].(string)
 
 		defaultFormat, _ = args["--format"].(string)
+		junit, _         = args["--junit"].(string)
 	)
 
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	gate, err := NewStatusGate(args)
+	if err != nil {
+		return err
+	}
+
 	if defaultFormat == "" {
 		defaultFormat = defaultFileStatusFormat
 	}
@@ -38,19 +51,23 @@ func doFilesStatus(
 
 	var table = NewTableWriter(os.Stdout)
 
-	var progress = Progress{
-		Total: len(files),
+	statuses, err := getFilesStatus(ctx, client, config, files)
+	if err != nil {
+		return err
 	}
 
-	for _, file := range files {
-		status, err := client.GetFileStatus(project, file.FileURI)
+	for index, file := range files {
+		status := statuses[index]
+
+		gate.Check(status)
+
+		data := NewFormatData(config, file, "", info.SourceLocaleID)
+
+		fileConfig, err := config.GetFileConfig(data.FileURI)
 		if err != nil {
 			return err
 		}
 
-		progress.Increment()
-		progress.Flush()
-
 		translations := status.Items
 
 		translations = append(
@@ -64,15 +81,17 @@ func doFilesStatus(
 		)
 
 		for _, translation := range translations {
+			data.Locale = translation.LocaleID
+			data.AppLocale = config.GetAppLocale(
+				fileConfig,
+				translation.LocaleID,
+			)
+
 			path, err := executeFileFormat(
 				config,
-				file,
 				defaultFormat,
 				usePullFormat,
-				map[string]interface{}{
-					"FileURI": file.FileURI,
-					"Locale":  translation.LocaleID,
-				},
+				data,
 			)
 			if err != nil {
 				return err
@@ -107,6 +126,34 @@ func doFilesStatus(
 				state = "missing"
 			}
 
+			if output.IsStructured() {
+				record := FileStatusRecord{
+					FileURI:  file.FileURI,
+					Path:     path,
+					Locale:   locale,
+					Source:   translation.LocaleID == "",
+					State:    state,
+					Progress: 100,
+					Strings:  translation.CompletedStringCount,
+					Words:    translation.CompletedWordCount,
+				}
+
+				if !record.Source {
+					record.Progress = -1
+					if status.TotalStringCount > 0 {
+						record.Progress = int(
+							100 *
+								float64(translation.CompletedStringCount) /
+								float64(status.TotalStringCount),
+						)
+					}
+				}
+
+				output.Add(record)
+
+				continue
+			}
+
 			writeFileStatus(table, map[string]string{
 				"Path":     path,
 				"Locale":   locale,
@@ -118,12 +165,81 @@ func doFilesStatus(
 		}
 	}
 
-	err = RenderTable(table)
+	if output.IsStructured() {
+		err = output.Flush()
+	} else {
+		err = RenderTable(table)
+	}
+
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if !gate.Enabled() {
+		return nil
+	}
+
+	if junit != "" {
+		err = gate.WriteJUnit(junit)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = gate.Render(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	return gate.Err()
+}
+
+// getFilesStatus fetches statuses of given files concurrently; statuses are
+// returned in the same order as files.
+func getFilesStatus(
+	ctx context.Context,
+	client smartling.ClientInterface,
+	config Config,
+	files []smartling.File,
+) ([]*smartling.FileStatus, error) {
+	var (
+		pool     = NewThreadPool(ctx, config.Threads)
+		statuses = make([]*smartling.FileStatus, len(files))
+		errs     = make([]error, len(files))
+
+		progress = Progress{
+			Total: len(files),
+		}
+	)
+
+	for index, file := range files {
+		// func closure required to pass different file objects to goroutines
+		func(index int, file smartling.File) {
+			pool.Do(func() {
+				statuses[index], errs[index] = client.GetFileStatus(
+					config.ProjectID,
+					file.FileURI,
+				)
+
+				progress.Increment()
+				progress.Flush()
+			})
+		}(index, file)
+	}
+
+	pool.Wait()
+
+	if ctx.Err() != nil {
+		return nil, newInterruptedError("checked", progress.Current, len(files))
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return statuses, nil
 }
 
 func writeFileStatus(table *tabwriter.Writer, row map[string]string) {
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cuyl/smartling-cli/mocks"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getStatusFiles(count int) []smartling.File {
+	files := make([]smartling.File, count)
+
+	for i := range files {
+		files[i].FileURI = fmt.Sprintf("/res/%d.json", i)
+	}
+
+	return files
+}
+
+func TestGetFilesStatusKeepsOrder(t *testing.T) {
+	files := getStatusFiles(5)
+
+	// statuses are returned in reverse order, last file comes first
+	client := &mocks.ClientInterface{}
+
+	for i, file := range files {
+		client.On("GetFileStatus", "test", file.FileURI).
+			After(time.Duration(len(files)-i) * 10 * time.Millisecond).
+			Return(&smartling.FileStatus{
+				File:             file,
+				TotalStringCount: i,
+			}, nil).
+			Once()
+	}
+
+	config := getConfig()
+	config.Threads = len(files)
+
+	statuses, err := getFilesStatus(context.Background(), client, config, files)
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+
+	if assert.Len(t, statuses, len(files)) {
+		for i, file := range files {
+			assert.Equal(t, file.FileURI, statuses[i].FileURI)
+			assert.Equal(t, i, statuses[i].TotalStringCount)
+		}
+	}
+}
+
+func TestGetFilesStatusReturnsError(t *testing.T) {
+	files := getStatusFiles(3)
+
+	failure := smartling.APIError{
+		Cause:   errors.New("file not found"),
+		Headers: &http.Header{},
+	}
+
+	client := &mocks.ClientInterface{}
+	client.On("GetFileStatus", "test", files[0].FileURI).
+		Return(&smartling.FileStatus{File: files[0]}, nil)
+	client.On("GetFileStatus", "test", files[1].FileURI).
+		Return(nil, failure)
+	client.On("GetFileStatus", "test", files[2].FileURI).
+		Return(&smartling.FileStatus{File: files[2]}, nil)
+
+	config := getConfig()
+	config.Threads = 3
+
+	_, err := getFilesStatus(context.Background(), client, config, files)
+	assert.True(t, errors.Is(err, failure))
+}
+
+func TestGetFilesStatusCountsProgressOnInterrupt(t *testing.T) {
+	files := getStatusFiles(4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// first two files are checked concurrently, interrupt comes while they
+	// are in progress, so remaining files are never checked
+	client := &mocks.ClientInterface{}
+	client.On("GetFileStatus", "test", files[0].FileURI).
+		After(20*time.Millisecond).
+		Return(&smartling.FileStatus{File: files[0]}, nil).
+		Once()
+	client.On("GetFileStatus", "test", files[1].FileURI).
+		Run(func(_ mock.Arguments) { cancel() }).
+		After(10*time.Millisecond).
+		Return(&smartling.FileStatus{File: files[1]}, nil).
+		Once()
+
+	config := getConfig()
+	config.Threads = 2
+
+	_, err := getFilesStatus(ctx, client, config, files)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "interrupted, 2 of 4 file(s) were checked")
+	client.AssertExpectations(t)
+}
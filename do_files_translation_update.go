@@ -2,53 +2,61 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
-	"strings"
-
-	rate "golang.org/x/time/rate"
-
 	smartling "github.com/Smartling/api-sdk-go"
-	"github.com/gobwas/glob"
 	"github.com/reconquest/hierr-go"
 )
 
-func SearchLocale(list []smartling.Locale, locale string) (*smartling.Locale) {
-	for _, _locale := range list {
-		if _locale.LocaleID == locale {
-			return &_locale
-		}
-	}
-	return nil
-}
-
-type GlobConfigPair struct {
-	Glob glob.Glob
-	Config FileConfig
-}
-
-// UploadItem is ...
+// UploadItem is local translation file, which is about to be imported.
 type UploadItem struct {
-	SourceFile smartling.File
+	SourceFile      smartling.File
 	TranslationFile string
-	Locale string
+	Locale          string
+
+	// Path is TranslationFile resolved relative to config file directory.
+	Path string
 }
 
 func doFilesTranslationUpdate(
+	ctx context.Context,
 	client *smartling.Client,
 	config Config,
 	args map[string]interface{},
 ) error {
 	var (
-		project     = config.ProjectID
-		uri, useURI = args["[uri]"].(string)
-		// sourceLocale string
-		branch, useBranch   = args["--branch"].(string)
-		
+		project           = config.ProjectID
+		uri, useURI       = args["<uri>"].(string)
+		branch, useBranch = args["--branch"].(string)
+		failFast, _       = args["--fail-fast"].(bool)
+		locales, _        = args["--locale"].([]string)
+		sinceValue, _     = args["--since"].(string)
+		sourceLocale, _   = args["--source-locale"].(string)
 	)
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	importErrors, err := NewImportErrorReport(args)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+
+	if sinceValue != "" {
+		since, err = parseSince(sinceValue, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
 	if !useURI || uri == "" {
 		uri = "**"
 	}
@@ -56,23 +64,12 @@ func doFilesTranslationUpdate(
 		uri = branch + "/**"
 	}
 
-	// config.Locales
 	info, err := client.GetProjectDetails(project)
 
 	if err != nil {
 		return err
 	}
-	
-	// sourceLocale = info.SourceLocaleID
-
-	// TODO: add project target language
-	// https://api-reference.smartling.com/#operation/addLocaleToProject
-	// Smartling/api-sdk-go do not have the api above.
-	// for _, locale := range config.Locales {
-	// 	if l := SearchLocale(info.TargetLocales, locale.Smartling); l == nil {
-	// 		// client.up
-	// 	}
-	// } 
+
 	files, err := globFilesRemote(
 		client,
 		project,
@@ -87,92 +84,216 @@ func doFilesTranslationUpdate(
 		logger.Infof("No files found %s", uri)
 	}
 
-	var globConfigList []GlobConfigPair
-	for pattern, section := range config.Files {
-		pattern, err := glob.Compile(pattern, '/')
+	var (
+		uploadItems []UploadItem
+		skipped     = map[string]int{}
+
+		// matched is used to warn about --locale values, which don't
+		// match any of project locales
+		matched = map[string]bool{}
+	)
+
+	// plan adds local file to import plan if it exists and was modified
+	// since time specified by --since option
+	plan := func(file smartling.File, data FormatData, locale string) {
+		// same default format as for pull command is used, so pulled
+		// files are found
+		path, err := executeFileFormat(
+			config,
+			defaultFileStatusFormat,
+			usePullFormat,
+			data,
+		)
 		if err != nil {
-			logger.Error(NewError(
-				err,
-				"Search file URI is malformed. Check out help for more "+
-					"information about search patterns.",
-			))
-			continue
+			logger.Error(hierr.Errorf(err, "format failed"),
+				"Check that specified file format syntax.",
+			)
+			skipped["with invalid format"]++
+			return
 		}
-		globConfigList = append(globConfigList, GlobConfigPair {
-			Glob: pattern,
-			Config: section,
+
+		resolved := filepath.Join(filepath.Dir(config.path), path)
+
+		stat, err := os.Stat(resolved)
+		if err != nil {
+			logger.Infof("File not found: %s", path)
+			skipped["not found"]++
+			return
+		}
+
+		if stat.ModTime().Before(since) {
+			logger.Infof(
+				"%s is not modified since %s, skipping",
+				path,
+				since.Format(time.RFC3339),
+			)
+			skipped["not modified since "+sinceValue]++
+			return
+		}
+
+		uploadItems = append(uploadItems, UploadItem{
+			SourceFile:      file,
+			TranslationFile: path,
+			Locale:          locale,
+			Path:            resolved,
 		})
 	}
 
-	var uploadItems []UploadItem
-	for _, file :=  range files {
-		targetFileURI := file.FileURI
-		if useBranch {
-			targetFileURI = strings.TrimPrefix(file.FileURI, branch + "/")
-		}
-		// if section.Push.Type != "" {
-		// 	patterns = append(patterns, pattern)
-		// }
-
-		for _, globConfig := range globConfigList {
-			if globConfig.Glob.Match(targetFileURI) {
-				for _, locale := range info.TargetLocales {
-					AppLocale := locale.LocaleID
-					if _AppLocale, ok := config.LocaleToAppLocaleMap[locale.LocaleID]; ok {
-						AppLocale = _AppLocale
-					}
-
-					path, err := executeFileFormat(
-						config,
-						file,
-						globConfig.Config.Pull.Format,
-						usePullFormat,
-						map[string]interface{}{
-							"AppLocale": AppLocale,
-							"FileURI":   targetFileURI,
-							"Locale":    locale.LocaleID,
-						},
-					)
-					if err != nil {
-						logger.Error(hierr.Errorf(err, "format failed"),
-							"Check that specified file format syntax.",
-						)
-						continue
-					}
-					if _, err := os.Stat(filepath.Join(filepath.Dir(config.path), path)); err == nil {
-						uploadItems = append(uploadItems, UploadItem {
-							SourceFile:      file,
-							TranslationFile: path,
-							Locale:          locale.LocaleID,
-						})
-					} else {
-						logger.Infof("File not found: %s", path)
-					}
-				}
+	// filter returns true if locale, specified by any of given IDs, is
+	// requested by --locale option
+	filter := func(ids ...string) bool {
+		if len(locales) == 0 {
+			return true
+		}
+
+		match := getMatchingLocale(locales, ids...)
+		if match == "" {
+			return false
+		}
+
+		matched[match] = true
+
+		return true
+	}
+
+	for _, file := range files {
+		data := NewFormatData(config, file, branch, info.SourceLocaleID)
+
+		sections, err := config.getFileSections(data.FileURI)
+		if err != nil {
+			return err
+		}
+
+		// only files matching any of files sections are imported
+		if len(sections) == 0 ||
+			len(sections) == 1 && sections[0] == defaultFileSection {
+			continue
+		}
+
+		fileConfig, err := config.GetFileConfig(data.FileURI)
+		if err != nil {
+			return err
+		}
+
+		for _, locale := range info.TargetLocales {
+			data.Locale = locale.LocaleID
+			data.AppLocale = config.GetAppLocale(fileConfig, locale.LocaleID)
+
+			if !filter(data.Locale, data.AppLocale) {
+				continue
 			}
+
+			plan(file, data, locale.LocaleID)
+		}
+
+		if sourceLocale == "" {
+			continue
+		}
+
+		// source-language files can be imported using application locale
+		// too, which can be mapped differently for every file
+		fileSourceLocale := config.GetLocale(fileConfig, sourceLocale)
+
+		if filter(
+			fileSourceLocale,
+			config.GetAppLocale(fileConfig, fileSourceLocale),
+		) {
+			// source-language file is located using empty locale, same as
+			// for pull --source
+			data.Locale = ""
+			data.AppLocale = ""
+
+			plan(file, data, fileSourceLocale)
+		}
+	}
+
+	for _, locale := range locales {
+		if !matched[locale] {
+			logger.Warningf(
+				"locale %q does not match any of project locales",
+				locale,
+			)
 		}
 	}
+
 	if len(uploadItems) == 0 {
 		logger.Infof("No items found %s", uri)
 	}
 
-	pool := NewThreadPool(config.Threads)
+	err = printImportPlan(os.Stderr, uploadItems, skipped)
+	if err != nil {
+		return err
+	}
+
+	if isDryRun(args) {
+		reportDryRun("imported")
+
+		if output.IsStructured() {
+			for _, item := range uploadItems {
+				output.Add(ImportRecord{
+					File:     item.TranslationFile,
+					FileURI:  item.SourceFile.FileURI,
+					Locale:   item.Locale,
+					FileType: string(item.SourceFile.FileType),
+					Status:   "planned",
+				})
+			}
+
+			return output.Flush()
+		}
+
+		table := NewTableWriter(os.Stdout)
+
+		for _, item := range uploadItems {
+			fmt.Fprintf(
+				table,
+				"%s\t%s\t%s\n",
+				item.TranslationFile,
+				item.SourceFile.FileURI,
+				item.Locale,
+			)
+		}
+
+		return RenderTable(table)
+	}
+
+	var (
+		pool     = NewThreadPool(ctx, config.Threads)
+		report   = NewFailureReport("import", failFast)
+		imported int32
+		stopped  int32
+	)
+
+	for index, item := range uploadItems {
+		if report.Stopped() {
+			atomic.AddInt32(&stopped, int32(len(uploadItems)-index))
+
+			break
+		}
 
-	rl := rate.NewLimiter(rate.Every(time.Millisecond*1500), 1)
-	ctx := context.Background()
-	for _, item := range uploadItems {
 		// func closure required to pass different file objects to goroutines
 		func(item UploadItem) {
-			pool.Do(func(){
+			scheduled := pool.Do(func() {
+				// other task could fail while this one was waiting for
+				// available thread
+				if report.Stopped() {
+					atomic.AddInt32(&stopped, 1)
+					return
+				}
 
-				contents, err := ioutil.ReadFile(item.TranslationFile)
+				fail := func(err error) {
+					report.Add(Failure{
+						FileURI: item.SourceFile.FileURI,
+						Locale:  item.Locale,
+						Path:    item.TranslationFile,
+						Error:   err,
+					})
+				}
+
+				contents, err := ioutil.ReadFile(item.Path)
 
 				if err != nil {
-					logger.Error(NewError(
-						hierr.Errorf(err, "unable to read file for import"),
-						"Check that specified file exists and you have permissions "+
-							"to read it.",
-					))
+					fail(hierr.Errorf(err, "unable to read file for import"))
 					return
 				}
 
@@ -195,28 +316,25 @@ func doFilesTranslationUpdate(
 					request.Overwrite,
 				)
 
-				rl.Wait(ctx)
 				result, err := client.Import(project, item.Locale, request)
 
 				if err != nil {
-					logger.Error(hierr.Errorf(
+					fail(hierr.Errorf(
 						err,
 						`unable to import file "%s" (original "%s")`,
 						item.TranslationFile,
 						item.SourceFile.FileURI,
 					))
+					return
 				}
-				if len(result.TranslationImportErrors) != 0 {
-					for _, importErrorItem := range result.TranslationImportErrors {
-						logger.Warningf(
-							"[%s] key: %s messages: %v hash: %s",
-							item.TranslationFile,
-							importErrorItem.ImportKey,
-							importErrorItem.Messages,
-							importErrorItem.StringHashcode,
-						)
-					}
-				}
+
+				importErrors.Add(
+					item.TranslationFile,
+					item.SourceFile.FileURI,
+					item.Locale,
+					result,
+				)
+
 				logger.Infof(
 					"%s imported [%d strings %d words]",
 					item.TranslationFile,
@@ -224,10 +342,61 @@ func doFilesTranslationUpdate(
 					result.WordCount,
 				)
 
+				output.Add(ImportRecord{
+					File:     item.TranslationFile,
+					FileURI:  item.SourceFile.FileURI,
+					Locale:   item.Locale,
+					FileType: string(item.SourceFile.FileType),
+					Status:   "imported",
+					Strings:  result.StringCount,
+					Words:    result.WordCount,
+				})
+
+				atomic.AddInt32(&imported, 1)
 			})
-			}(item)
-		}
+
+			if !scheduled {
+				report.Add(Failure{
+					FileURI: item.SourceFile.FileURI,
+					Locale:  item.Locale,
+					Path:    item.TranslationFile,
+					Error:   errInterrupted,
+				})
+			}
+		}(item)
+	}
 	pool.Wait()
 
-	return nil
+	if stopped > 0 {
+		logger.Warningf(
+			"%d file(s) were not imported because of --fail-fast mode",
+			stopped,
+		)
+	}
+
+	err = output.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = report.Render(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	err = importErrors.Write()
+	if err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return newInterruptedError("imported", int(imported), len(uploadItems))
+	}
+
+	err = report.Err()
+	if err != nil {
+		return err
+	}
+
+	return importErrors.Err()
 }
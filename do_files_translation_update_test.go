@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslationUpdateFiltersLocales(t *testing.T) {
+	api, client := newFakeAPI(t)
+
+	config, args := setupTranslationUpdate(t, "de-DE", "fr-FR", "ja-JP")
+	args["--locale"] = []string{"fr-FR", "ja-JP"}
+
+	err := doFilesTranslationUpdate(context.Background(), client, config, args)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fr-FR", "ja-JP"}, api.Imports())
+}
+
+func TestTranslationUpdateSkipsNotModified(t *testing.T) {
+	api, client := newFakeAPI(t)
+
+	config, args := setupTranslationUpdate(t, "de-DE", "fr-FR", "ja-JP")
+	args["--since"] = "1h"
+
+	modified := time.Now().Add(-2 * time.Hour)
+
+	for _, locale := range []string{"de-DE", "ja-JP"} {
+		err := os.Chtimes(
+			filepath.Join(filepath.Dir(config.path), "res", locale+".json"),
+			modified,
+			modified,
+		)
+		assert.NoError(t, err)
+	}
+
+	err := doFilesTranslationUpdate(context.Background(), client, config, args)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fr-FR"}, api.Imports())
+}
+
+func TestTranslationUpdateSourceLocaleFromFileConfig(t *testing.T) {
+	api, client := newFakeAPI(t)
+
+	config, args := setupTranslationUpdate(t, "source")
+
+	// application locale is mapped only for matching files
+	err := ioutil.WriteFile(config.path, []byte(`
+project_id: project
+threads: 1
+locales:
+    - application: en
+      smartling: en-GB
+files:
+    "/res/**":
+        locales:
+            - application: en
+              smartling: en-US
+        pull:
+            format: "res/{{with .Locale}}{{.}}{{else}}source{{end}}.json"
+`), 0644)
+	assert.NoError(t, err)
+
+	config, err = NewConfig(config.path)
+	assert.NoError(t, err)
+
+	args["--source-locale"] = "en"
+	args["--locale"] = []string{"en"}
+
+	err = doFilesTranslationUpdate(context.Background(), client, config, args)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"en-US"}, api.Imports())
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"regexp"
 
 	smartling "github.com/Smartling/api-sdk-go"
@@ -16,7 +18,7 @@ import (
 func doInit(config Config, args map[string]interface{}) error {
 	fmt.Printf("Generating %s...\n\n", config.path)
 
-	prompt := func(
+	var prompt initPrompt = func(
 		message string,
 		value interface{},
 		zero bool,
@@ -63,16 +65,9 @@ func doInit(config Config, args map[string]interface{}) error {
 		config.UserID = input.UserID
 	}
 
-	prompt(
-		"Smartling API V2.0 Token Secret",
-		config.Secret,
-		config.Secret == "",
-		true,
-		&input.Secret,
-	)
-
-	if input.Secret != "" {
-		config.Secret = input.Secret
+	err := promptSecret(&config, args["--dry-run"].(bool), prompt)
+	if err != nil {
+		return err
 	}
 
 	prompt(
@@ -100,7 +95,7 @@ func doInit(config Config, args map[string]interface{}) error {
 	}
 
 	var result bytes.Buffer
-	err := configTemplate.Execute(&result, config)
+	err = configTemplate.Execute(&result, config)
 	if err != nil {
 		return hierr.Errorf(
 			err,
@@ -112,7 +107,7 @@ func doInit(config Config, args map[string]interface{}) error {
 
 	fmt.Println("Testing connection to Smartling API...")
 
-	client, err := createClient(config, args)
+	client, err := createClient(context.Background(), config, args)
 	if err != nil {
 		return hierr.Errorf(
 			err,
@@ -157,3 +152,203 @@ func doInit(config Config, args map[string]interface{}) error {
 
 	return nil
 }
+
+type initPrompt func(
+	message string,
+	value interface{},
+	zero bool,
+	hidden bool,
+	variable interface{},
+)
+
+const (
+	secretStorageConfig  = "config"
+	secretStorageFile    = "file"
+	secretStorageCommand = "command"
+	secretStorageEnv     = "env"
+
+	defaultSecretFile = ".smartling.secret"
+	defaultSecretEnv  = "SMARTLING_SECRET"
+)
+
+// promptSecret asks where token secret should be stored and reads secret
+// from chosen source, so it can be used for testing connection.
+func promptSecret(config *Config, dryRun bool, prompt initPrompt) error {
+	storage := secretStorageConfig
+
+	switch {
+	case config.SecretFile != "":
+		storage = secretStorageFile
+
+	case config.SecretCommand != "":
+		storage = secretStorageCommand
+
+	case config.SecretEnv != "":
+		storage = secretStorageEnv
+	}
+
+	storage, err := input.DefaultUI().Select(
+		"Where to store Smartling API V2.0 Token Secret "+
+			"(storing it outside of config file is recommended)",
+		[]string{
+			secretStorageConfig,
+			secretStorageFile,
+			secretStorageCommand,
+			secretStorageEnv,
+		},
+		&input.Options{
+			Default: storage,
+			Loop:    true,
+		},
+	)
+	if err != nil {
+		if input.ErrInterrupted == err {
+			os.Exit(1)
+		}
+
+		return hierr.Errorf(err, "unable to read secret storage")
+	}
+
+	var (
+		secret = config.Secret
+		read   string
+
+		file    = config.SecretFile
+		command = config.SecretCommand
+		env     = config.SecretEnv
+	)
+
+	config.SecretFile = ""
+	config.SecretCommand = ""
+	config.SecretEnv = ""
+
+	askSecret := func() {
+		prompt(
+			"Smartling API V2.0 Token Secret",
+			secret,
+			secret == "",
+			true,
+			&read,
+		)
+
+		if read != "" {
+			secret = read
+		}
+	}
+
+	switch storage {
+	case secretStorageConfig:
+		askSecret()
+
+	case secretStorageFile:
+		config.SecretFile = file
+		if file == "" {
+			config.SecretFile = defaultSecretFile
+		}
+
+		prompt(
+			"Path to secret file (relative to config)",
+			config.SecretFile,
+			false,
+			false,
+			&read,
+		)
+
+		if read != "" {
+			config.SecretFile = read
+		}
+
+		config.Secret = ""
+
+		err = resolveSecret(config)
+		if err == nil {
+			secret = config.Secret
+
+			break
+		}
+
+		// secret file does not exist yet, so it's created with secret
+		// entered by user
+		read = ""
+		askSecret()
+
+		path := config.SecretFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(config.path), path)
+		}
+
+		if dryRun {
+			fmt.Printf("Configured for Dry run. Not writing %s.\n", path)
+
+			break
+		}
+
+		err = ioutil.WriteFile(path, []byte(secret+"\n"), 0600)
+		if err != nil {
+			return hierr.Errorf(err, `unable to write secret file "%s"`, path)
+		}
+
+		fmt.Printf(
+			"Secret is written to %s, do not forget to add it to .gitignore.\n",
+			path,
+		)
+
+	case secretStorageCommand:
+		// command is read as is, because it usually contains spaces
+		config.SecretCommand, err = input.DefaultUI().Ask(
+			"Command which prints secret to stdout",
+			&input.Options{
+				Default:  command,
+				Required: true,
+				Loop:     true,
+			},
+		)
+		if err != nil {
+			if input.ErrInterrupted == err {
+				os.Exit(1)
+			}
+
+			return hierr.Errorf(err, "unable to read secret command")
+		}
+
+		config.Secret = ""
+
+		err = resolveSecret(config)
+		if err != nil {
+			return err
+		}
+
+		secret = config.Secret
+
+	case secretStorageEnv:
+		config.SecretEnv = env
+		if env == "" {
+			config.SecretEnv = defaultSecretEnv
+		}
+
+		prompt(
+			"Environment variable with secret",
+			config.SecretEnv,
+			false,
+			false,
+			&read,
+		)
+
+		if read != "" {
+			config.SecretEnv = read
+		}
+
+		config.Secret = ""
+
+		err = resolveSecret(config)
+		if err != nil {
+			return err
+		}
+
+		secret = config.Secret
+	}
+
+	config.Secret = secret
+
+	return nil
+}
@@ -13,6 +13,11 @@ func doProjectsInfo(
 	config Config,
 	args map[string]interface{},
 ) error {
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
 	details, err := client.GetProjectDetails(config.ProjectID)
 	if err != nil {
 		if _, ok := err.(smartling.NotFoundError); ok {
@@ -26,14 +31,27 @@ func doProjectsInfo(
 		)
 	}
 
-	table := NewTableWriter(os.Stdout)
-
 	status := "active"
 
 	if details.Archived {
 		status = "archived"
 	}
 
+	if output.IsStructured() {
+		output.Add(ProjectDetailsRecord{
+			ProjectID:               details.ProjectID,
+			AccountUID:              details.AccountUID,
+			ProjectName:             details.ProjectName,
+			SourceLocaleID:          details.SourceLocaleID,
+			SourceLocaleDescription: details.SourceLocaleDescription,
+			Status:                  status,
+		})
+
+		return output.Flush()
+	}
+
+	table := NewTableWriter(os.Stdout)
+
 	info := [][]interface{}{
 		{"ID", details.ProjectID},
 		{"ACCOUNT", details.AccountUID},
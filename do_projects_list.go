@@ -17,6 +17,11 @@ func doProjectsList(
 		short = args["--short"].(bool)
 	)
 
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
 	projects, err := client.ListProjects(
 		config.AccountID,
 		smartling.ProjectsListRequest{},
@@ -28,6 +33,18 @@ func doProjectsList(
 		)
 	}
 
+	if output.IsStructured() {
+		for _, project := range projects.Items {
+			output.Add(ProjectRecord{
+				ProjectID:      project.ProjectID,
+				ProjectName:    project.ProjectName,
+				SourceLocaleID: project.SourceLocaleID,
+			})
+		}
+
+		return output.Flush()
+	}
+
 	table := NewTableWriter(os.Stdout)
 
 	for _, project := range projects.Items {
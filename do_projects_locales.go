@@ -20,6 +20,11 @@ func doProjectsLocales(
 		source, _ = args["--source"].(bool)
 	)
 
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
 	if args["--format"] == nil {
 		args["--format"] = defaultProjectsLocalesFormat
 	}
@@ -42,6 +47,27 @@ func doProjectsLocales(
 		)
 	}
 
+	if output.IsStructured() {
+		if source {
+			output.Add(LocaleRecord{
+				LocaleID:    details.SourceLocaleID,
+				Description: details.SourceLocaleDescription,
+				Enabled:     true,
+				Source:      true,
+			})
+		} else {
+			for _, locale := range details.TargetLocales {
+				output.Add(LocaleRecord{
+					LocaleID:    locale.LocaleID,
+					Description: locale.Description,
+					Enabled:     locale.Enabled,
+				})
+			}
+		}
+
+		return output.Flush()
+	}
+
 	table := NewTableWriter(os.Stdout)
 
 	if source {
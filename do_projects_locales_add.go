@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+
+	smartling "github.com/Smartling/api-sdk-go"
+)
+
+func doProjectsLocalesAdd(
+	client *smartling.Client,
+	config Config,
+	args map[string]interface{},
+) error {
+	var (
+		project    = config.ProjectID
+		locales, _ = args["<locale-id>"].([]string)
+	)
+
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	existing, err := getProjectLocales(client, project)
+	if err != nil {
+		return err
+	}
+
+	for index, locale := range locales {
+		locales[index], err = getSmartlingLocale(config, locale)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = addProjectLocales(client, args, project, locales, existing, output)
+	if err != nil {
+		return err
+	}
+
+	return output.Flush()
+}
+
+// addProjectLocales adds locales, which are not yet added to project, and
+// reports status for every given locale.
+func addProjectLocales(
+	client *smartling.Client,
+	args map[string]interface{},
+	project string,
+	locales []string,
+	existing map[string]bool,
+	output *Output,
+) error {
+	if isDryRun(args) {
+		reportDryRun("added")
+	}
+
+	for _, locale := range locales {
+		status := "added"
+
+		switch {
+		case existing[locale]:
+			status = "exists"
+
+		case isDryRun(args):
+			status = "planned"
+
+		default:
+			err := addProjectLocale(client, project, locale)
+			if err != nil {
+				return err
+			}
+		}
+
+		existing[locale] = true
+
+		if output.IsStructured() {
+			output.Add(ProjectLocaleRecord{
+				LocaleID: locale,
+				Status:   status,
+			})
+		} else {
+			fmt.Printf("%s %s\n", locale, status)
+		}
+	}
+
+	return nil
+}
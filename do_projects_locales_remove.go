@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+
+	smartling "github.com/Smartling/api-sdk-go"
+)
+
+func doProjectsLocalesRemove(
+	client *smartling.Client,
+	config Config,
+	args map[string]interface{},
+) error {
+	var (
+		project    = config.ProjectID
+		locales, _ = args["<locale-id>"].([]string)
+	)
+
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	existing, err := getProjectLocales(client, project)
+	if err != nil {
+		return err
+	}
+
+	if isDryRun(args) {
+		reportDryRun("removed")
+	}
+
+	for _, locale := range locales {
+		locale, err := getSmartlingLocale(config, locale)
+		if err != nil {
+			return err
+		}
+
+		status := "removed"
+
+		switch {
+		case !existing[locale]:
+			status = "not found"
+
+		case isDryRun(args):
+			status = "planned"
+
+		default:
+			err := removeProjectLocale(client, project, locale)
+			if err != nil {
+				return err
+			}
+		}
+
+		delete(existing, locale)
+
+		if output.IsStructured() {
+			output.Add(ProjectLocaleRecord{
+				LocaleID: locale,
+				Status:   status,
+			})
+		} else {
+			fmt.Printf("%s %s\n", locale, status)
+		}
+	}
+
+	return output.Flush()
+}
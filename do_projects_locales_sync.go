@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	smartling "github.com/Smartling/api-sdk-go"
+)
+
+// doProjectsLocalesSync adds locales from config file, which are missing in
+// project. Project locales, which are not listed in config, are only
+// reported, because removing them can't be undone.
+func doProjectsLocalesSync(
+	client *smartling.Client,
+	config Config,
+	args map[string]interface{},
+) error {
+	var (
+		project = config.ProjectID
+	)
+
+	if len(config.Locales) == 0 {
+		return NewError(
+			errors.New("no locales specified in config file"),
+
+			`Add "locales" section with application and Smartling locales `+
+				`to config file.`,
+		)
+	}
+
+	output, err := NewOutput(args)
+	if err != nil {
+		return err
+	}
+
+	existing, err := getProjectLocales(client, project)
+	if err != nil {
+		return err
+	}
+
+	var (
+		locales  []string
+		declared = map[string]bool{}
+	)
+
+	for _, locale := range config.Locales {
+		if !declared[locale.Smartling] {
+			locales = append(locales, locale.Smartling)
+		}
+
+		declared[locale.Smartling] = true
+	}
+
+	var extra []string
+
+	for locale := range existing {
+		if !declared[locale] {
+			extra = append(extra, locale)
+		}
+	}
+
+	sort.Strings(extra)
+
+	err = addProjectLocales(client, args, project, locales, existing, output)
+	if err != nil {
+		return err
+	}
+
+	for _, locale := range extra {
+		if output.IsStructured() {
+			output.Add(ProjectLocaleRecord{
+				LocaleID: locale,
+				Status:   "not in config",
+			})
+		} else {
+			fmt.Printf("%s not in config\n", locale)
+		}
+	}
+
+	return output.Flush()
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -9,6 +11,15 @@ import (
 	"github.com/reconquest/hierr-go"
 )
 
+// downloadStatus describes what happened with local file after download.
+type downloadStatus string
+
+const (
+	downloadStatusCreated   downloadStatus = "created"
+	downloadStatusUpdated   downloadStatus = "updated"
+	downloadStatusUnchanged downloadStatus = "unchanged"
+)
+
 func downloadFile(
 	client *smartling.Client,
 	project string,
@@ -16,7 +27,7 @@ func downloadFile(
 	locale string,
 	path string,
 	retrievalType smartling.RetrievalType,
-) error {
+) (downloadStatus, error) {
 	var (
 		reader io.Reader
 		err    error
@@ -25,7 +36,7 @@ func downloadFile(
 	if locale == "" {
 		reader, err = client.DownloadFile(project, file.FileURI)
 		if err != nil {
-			return hierr.Errorf(
+			return "", hierr.Errorf(
 				err,
 				`unable to download original file "%s" from project "%s"`,
 				file.FileURI,
@@ -39,7 +50,7 @@ func downloadFile(
 
 		reader, err = client.DownloadTranslation(project, locale, request)
 		if err != nil {
-			return hierr.Errorf(
+			return "", hierr.Errorf(
 				err,
 				`unable to download file "%s" from project "%s" (locale "%s")`,
 				file.FileURI,
@@ -49,31 +60,109 @@ func downloadFile(
 		}
 	}
 
+	// file is downloaded completely before touching local file, so network
+	// error will not leave truncated file behind
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", hierr.Errorf(
+			err,
+			`unable to download file "%s" contents (locale "%s")`,
+			file.FileURI,
+			locale,
+		)
+	}
+
+	return writeDownloadedFile(path, contents)
+}
+
+// writeDownloadedFile writes downloaded contents into file at given path
+// and reports whether file was created, updated or left unchanged. Mode of
+// existing file is preserved.
+func writeDownloadedFile(path string, contents []byte) (downloadStatus, error) {
+	status := downloadStatusCreated
+	mode := os.FileMode(0644)
+
+	existing, err := os.Stat(path)
+	if err == nil {
+		current, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", hierr.Errorf(
+				err,
+				`unable to read existing file "%s"`,
+				path,
+			)
+		}
+
+		// leave file untouched, so its modification time is preserved
+		if bytes.Equal(current, contents) {
+			return downloadStatusUnchanged, nil
+		}
+
+		status = downloadStatusUpdated
+		mode = existing.Mode().Perm()
+	}
+
 	err = os.MkdirAll(filepath.Dir(path), 0755)
 	if err != nil {
-		return hierr.Errorf(
+		return "", hierr.Errorf(
 			err,
 			`unable to create dirs hierarchy "%s" for downloaded file`,
 			path,
 		)
 	}
 
-	writer, err := os.Create(path)
+	err = writeFileAtomically(path, contents, mode)
+	if err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+// writeFileAtomically writes contents into temporary file in the same
+// directory and then renames it into place, so target file is either left
+// intact or is completely written.
+func writeFileAtomically(path string, contents []byte, mode os.FileMode) error {
+	writer, err := ioutil.TempFile(
+		filepath.Dir(path),
+		"."+filepath.Base(path)+".*",
+	)
 	if err != nil {
 		return hierr.Errorf(
 			err,
-			`unable to create output file "%s"`,
+			`unable to create temporary file for "%s"`,
 			path,
 		)
 	}
 
-	defer writer.Close()
+	temp := writer.Name()
+
+	_, err = writer.Write(contents)
+	if err == nil {
+		err = writer.Chmod(mode)
+	}
+
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
 
-	_, err = io.Copy(writer, reader)
 	if err != nil {
+		os.Remove(temp)
+
 		return hierr.Errorf(
 			err,
 			`unable to write file contents into "%s"`,
+			temp,
+		)
+	}
+
+	err = os.Rename(temp, path)
+	if err != nil {
+		os.Remove(temp)
+
+		return hierr.Errorf(
+			err,
+			`unable to move downloaded file into "%s"`,
 			path,
 		)
 	}
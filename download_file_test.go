@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDownloadedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "de-DE", "strings.json")
+
+	status, err := writeDownloadedFile(path, []byte("v1"))
+	assert.NoError(t, err)
+	assert.Equal(t, downloadStatusCreated, status)
+
+	stat, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), stat.Mode().Perm())
+
+	// same contents leave file untouched
+	modified := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(path, modified, modified))
+
+	status, err = writeDownloadedFile(path, []byte("v1"))
+	assert.NoError(t, err)
+	assert.Equal(t, downloadStatusUnchanged, status)
+
+	stat, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.True(t, stat.ModTime().Equal(modified))
+
+	// mode of existing file is preserved on update
+	assert.NoError(t, os.Chmod(path, 0600))
+
+	status, err = writeDownloadedFile(path, []byte("v2"))
+	assert.NoError(t, err)
+	assert.Equal(t, downloadStatusUpdated, status)
+
+	stat, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	contents, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", string(contents))
+}
+
+func TestWriteFileAtomicallyRemovesTemporaryFile(t *testing.T) {
+	directory := t.TempDir()
+
+	// file can't be renamed over directory, so write fails after temporary
+	// file is written
+	path := filepath.Join(directory, "strings.json")
+	assert.NoError(t, os.Mkdir(path, 0755))
+
+	err := writeFileAtomically(path, []byte("contents"), 0644)
+	assert.Error(t, err)
+
+	entries, err := ioutil.ReadDir(directory)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "strings.json", entries[0].Name())
+	assert.True(t, entries[0].IsDir())
+}
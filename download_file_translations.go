@@ -5,29 +5,28 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	smartling "github.com/Smartling/api-sdk-go"
 	"github.com/reconquest/hierr-go"
 )
 
-type formatData struct {
-	AppLocale  string
-	FileURI    string
-	Locale     string
-}
-
 func downloadFileTranslations(
 	client *smartling.Client,
 	config Config,
 	args map[string]interface{},
 	file smartling.File,
+	sourceLocale string,
+	state *State,
+	report *FailureReport,
+	output *Output,
 ) error {
 	var (
-		branch, useBranch = args["--branch"].(string)
+		branch, _ = args["--branch"].(string)
 		project   = config.ProjectID
-		LocaleToAppLocaleMap = config.LocaleToAppLocaleMap
 		directory = args["--directory"].(string)
 		source    = args["--source"].(bool)
+		full, _   = args["--full"].(bool)
 		locales   = args["--locale"].([]string)
 
 		format, formatGiven = args["--format"].(string)
@@ -35,10 +34,7 @@ func downloadFileTranslations(
 		retrieve, _         = args["--retrieve"].(string)
 	)
 
-	targetFileURI := file.FileURI
-	if useBranch {
-		targetFileURI = strings.TrimPrefix(file.FileURI, branch + "/")
-	}
+	data := NewFormatData(config, file, branch, sourceLocale)
 
 	progress = strings.TrimSuffix(progress, "%")
 	if progress == "" {
@@ -55,6 +51,11 @@ func downloadFileTranslations(
 
 	retrievalType := smartling.RetrievalType(retrieve)
 
+	fileConfig, err := config.GetFileConfig(data.FileURI)
+	if err != nil {
+		return err
+	}
+
 	if format == "" {
 		format = defaultFileStatusFormat
 	}
@@ -69,17 +70,46 @@ func downloadFileTranslations(
 		)
 	}
 
-	var translations []smartling.FileStatusTranslation
+	var (
+		translations []smartling.FileStatusTranslation
+
+		// modified contains last modification time for every locale, it's
+		// used to skip files which were not changed since last pull
+		modified = map[string]time.Time{}
+	)
 
 	if source {
 		translations = []smartling.FileStatusTranslation{
 			{LocaleID: ""},
 		}
+
+		modified[""] = file.LastUploaded.Time
 	} else {
 		translations = status.Items
+
+		// timestamps are retrieved even for full pull to record them
+		request := smartling.FileLastModifiedRequest{}
+		request.FileURI = file.FileURI
+
+		locales, err := client.LastModified(project, request)
+		if err != nil {
+			return hierr.Errorf(
+				err,
+				`unable to retrieve file "%s" last modification time`,
+				file.FileURI,
+			)
+		}
+
+		for _, locale := range locales.Items {
+			modified[locale.LocaleID] = locale.LastModified.Time
+		}
 	}
 
 	for _, locale := range translations {
+		if report.Stopped() {
+			break
+		}
+
 		var complete int64
 
 		if locale.CompletedStringCount > 0 {
@@ -109,31 +139,42 @@ func downloadFileTranslations(
 			}
 		}
 
-		AppLocale := locale.LocaleID
-		if _AppLocale, ok := LocaleToAppLocaleMap[locale.LocaleID]; ok {
-			AppLocale = _AppLocale
-		}
-		FileURI := file.FileURI
-		file.FileURI = targetFileURI
-		path, err := executeFileFormat(
-			config,
-			file,
-			format,
-			useFormat,
-			formatData {
-				AppLocale: AppLocale,
-				FileURI:   targetFileURI,
-				Locale:    locale.LocaleID,
-			},
-		)
-		file.FileURI = FileURI
+		data.Locale = locale.LocaleID
+		data.AppLocale = config.GetAppLocale(fileConfig, locale.LocaleID)
+
+		path, err := executeFileFormat(config, format, useFormat, data)
 		if err != nil {
-			return err
+			report.Add(Failure{
+				FileURI: file.FileURI,
+				Locale:  locale.LocaleID,
+				Error:   err,
+			})
+
+			continue
 		}
 
 		path = filepath.Join(directory, path)
 
-		err = downloadFile(
+		pulled := PullStateKey{
+			Project:   project,
+			FileURI:   file.FileURI,
+			Locale:    locale.LocaleID,
+			Retrieval: string(retrievalType),
+			Path:      path,
+		}
+
+		if !full && isFileExists(path) {
+			if state.IsPulled(pulled, modified[locale.LocaleID]) {
+				logger.Infof(
+					"%s is not modified since last pull, skipping",
+					path,
+				)
+
+				continue
+			}
+		}
+
+		result, err := downloadFile(
 			client,
 			project,
 			file,
@@ -142,17 +183,54 @@ func downloadFileTranslations(
 			retrievalType,
 		)
 		if err != nil {
-			return err
+			report.Add(Failure{
+				FileURI: file.FileURI,
+				Locale:  locale.LocaleID,
+				Path:    path,
+				Error:   err,
+			})
+
+			continue
 		}
 
-		if source {
-			fmt.Printf("downloaded %s\n", path)
+		state.SetPulled(pulled, modified[locale.LocaleID])
+
+		if output.IsStructured() {
+			record := PullRecord{
+				FileURI:  file.FileURI,
+				Locale:   locale.LocaleID,
+				Path:     path,
+				Progress: int(complete),
+				Status:   string(result),
+			}
+
+			if source {
+				record.Progress = 100
+			}
+
+			output.Add(record)
+		} else if source {
+			fmt.Printf("downloaded %s (%s)\n", path, result)
 		} else {
-			fmt.Printf("downloaded %s %d%%\n", path, int(complete))
+			fmt.Printf("downloaded %s %d%% (%s)\n", path, int(complete), result)
+		}
+	}
+
+	return nil
+}
+
+// getMatchingLocale returns locale from list, which matches any of given
+// locale IDs case-insensitively, or empty string.
+func getMatchingLocale(locales []string, ids ...string) string {
+	for _, filter := range locales {
+		for _, id := range ids {
+			if strings.EqualFold(filter, id) {
+				return filter
+			}
 		}
 	}
 
-	return err
+	return ""
 }
 
 func hasLocaleInList(locale string, locales []string) bool {
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+func isDryRun(args map[string]interface{}) bool {
+	dryRun, _ := args["--dry-run"].(bool)
+
+	return dryRun
+}
+
+// reportDryRun writes note about dry run to stderr, so stdout contains only
+// plan itself and can be processed by other tools.
+func reportDryRun(action string) {
+	fmt.Fprintf(
+		os.Stderr,
+		"Dry run, nothing will be %s. Following actions would be performed:\n",
+		action,
+	)
+}
+
+func formatDirectives(directives map[string]string) string {
+	if len(directives) == 0 {
+		return "-"
+	}
+
+	var pairs []string
+
+	for name, value := range directives {
+		pairs = append(pairs, name+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
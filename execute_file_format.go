@@ -1,9 +1,5 @@
 package main
 
-import (
-	smartling "github.com/Smartling/api-sdk-go"
-)
-
 var (
 	usePullFormat = func(config FileConfig) string {
 		return config.Pull.Format
@@ -12,12 +8,11 @@ var (
 
 func executeFileFormat(
 	config Config,
-	file smartling.File,
 	fallback string,
 	getter func(config FileConfig) string,
-	data interface{},
+	data FormatData,
 ) (string, error) {
-	local, err := config.GetFileConfig(file.FileURI)
+	local, err := config.GetFileConfig(data.FileURI)
 	if err != nil {
 		return "", err
 	}
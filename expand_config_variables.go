@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// configVariable matches $$ escape sequence, ${VAR} and ${VAR:-default}.
+var configVariable = regexp.MustCompile(
+	`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`,
+)
+
+// expandConfigVariables parses YAML document and replaces environment
+// variable references in all its values in place, so document can be decoded
+// with original line numbers. Reference without default value to unset
+// variable is an error. $$ is an escape sequence for literal $.
+func expandConfigVariables(path string, data []byte) (*yamlv3.Node, error) {
+	var document yamlv3.Node
+
+	err := yamlv3.Unmarshal(data, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(data), "\n")
+
+	err = walkConfigNode(&document, "", func(node *yamlv3.Node, key string) error {
+		if !strings.Contains(node.Value, "$") {
+			return nil
+		}
+
+		value, missing := expandConfigValue(node.Value)
+		if missing != "" {
+			err := MissingConfigVariableError{
+				ConfigPath: path,
+				KeyName:    key,
+				VarName:    missing,
+				Line:       node.Line,
+			}
+
+			if node.Line > 0 && node.Line <= len(lines) {
+				err.SourceLine = strings.TrimRight(lines[node.Line-1], "\r")
+			}
+
+			return err
+		}
+
+		if value == node.Value {
+			return nil
+		}
+
+		// plain values are re-resolved, so ${THREADS} can be used for
+		// numeric values
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+
+		node.Value = value
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+// expandConfigValue returns value with variable references replaced along
+// with name of the first referenced variable, which is not set.
+func expandConfigValue(value string) (string, string) {
+	var missing string
+
+	value = configVariable.ReplaceAllStringFunc(
+		value,
+		func(reference string) string {
+			if reference == "$$" {
+				return "$"
+			}
+
+			var (
+				match         = configVariable.FindStringSubmatch(reference)
+				name          = match[1]
+				hasDefault    = match[2] != ""
+				defaultValue  = match[3]
+				variable, set = os.LookupEnv(name)
+			)
+
+			switch {
+			case hasDefault && variable == "":
+				return defaultValue
+
+			case !set && missing == "":
+				missing = name
+			}
+
+			return variable
+		},
+	)
+
+	return value, missing
+}
+
+// walkConfigNode calls callback for every scalar value of document along
+// with its dot-separated key.
+func walkConfigNode(
+	node *yamlv3.Node,
+	key string,
+	callback func(*yamlv3.Node, string) error,
+) error {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, child := range node.Content {
+			err := walkConfigNode(child, key, callback)
+			if err != nil {
+				return err
+			}
+		}
+
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name := node.Content[i].Value
+			if key != "" {
+				name = key + "." + name
+			}
+
+			err := walkConfigNode(node.Content[i+1], name, callback)
+			if err != nil {
+				return err
+			}
+		}
+
+	case yamlv3.SequenceNode:
+		for i, child := range node.Content {
+			err := walkConfigNode(child, fmt.Sprintf("%s[%d]", key, i), callback)
+			if err != nil {
+				return err
+			}
+		}
+
+	case yamlv3.ScalarNode:
+		return callback(node, key)
+	}
+
+	return nil
+}
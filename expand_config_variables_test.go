@@ -0,0 +1,120 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigExpandsVariables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartling.yml")
+
+	err := ioutil.WriteFile(path, []byte(`
+project_id: "${TEST_SMARTLING_PROJECT}"
+threads: ${TEST_SMARTLING_THREADS:-7}
+proxy: http://${TEST_SMARTLING_PROXY_HOST:-localhost}:3128
+files:
+    "**.json":
+        push:
+            directives:
+                namespace: "app-${TEST_SMARTLING_PROJECT}"
+        pull:
+            format: "{{.Locale}}/$${HOME}.json"
+`), 0644)
+	assert.NoError(t, err)
+
+	os.Setenv("TEST_SMARTLING_PROJECT", "abc123")
+	defer os.Unsetenv("TEST_SMARTLING_PROJECT")
+
+	config, err := NewConfig(path)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "abc123", config.ProjectID)
+	assert.Equal(t, 7, config.Threads)
+	assert.Equal(t, "http://localhost:3128", config.Proxy)
+	assert.Equal(
+		t,
+		"app-abc123",
+		config.Files["**.json"].Push.Directives["namespace"],
+	)
+	assert.Equal(t, "{{.Locale}}/${HOME}.json", config.Files["**.json"].Pull.Format)
+
+	os.Unsetenv("TEST_SMARTLING_PROJECT")
+
+	_, err = NewConfig(path)
+	assert.Equal(
+		t,
+		MissingConfigVariableError{
+			ConfigPath: path,
+			KeyName:    "project_id",
+			VarName:    "TEST_SMARTLING_PROJECT",
+			Line:       2,
+			SourceLine: `project_id: "${TEST_SMARTLING_PROJECT}"`,
+		},
+		err,
+	)
+}
+
+func TestMissingConfigVariableErrorMessage(t *testing.T) {
+	err := MissingConfigVariableError{
+		ConfigPath: "/tmp/smartling.yml",
+		KeyName:    "files.**.json.push.directives.namespace",
+		VarName:    "SMARTLING_NAMESPACE",
+		Line:       12,
+		SourceLine: `                namespace: "app-${SMARTLING_NAMESPACE}"`,
+	}
+
+	assert.Equal(
+		t,
+		"ERROR: Cannot expand configuration parameter "+
+			"\"files.**.json.push.directives.namespace\": "+
+			"environment variable $SMARTLING_NAMESPACE is not set\n\n"+
+			"Please, either:\n"+
+			"- Set environment variable $SMARTLING_NAMESPACE;\n"+
+			"- Or specify default value for "+
+			"\"files.**.json.push.directives.namespace\" option "+
+			"in the configuration file:\n\n"+
+			"\t/tmp/smartling.yml:12\n"+
+			"\t\tnamespace: \"app-${SMARTLING_NAMESPACE:-DEFAULT_VALUE}\"",
+		err.Error(),
+	)
+}
+
+func TestNewConfigKeepsLineNumbers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartling.yml")
+
+	err := ioutil.WriteFile(path, []byte(`
+proxy: "http://${TEST_SMARTLING_PROXY_HOST:-localhost}:3128"
+files:
+    "**.json":
+        pull:
+            format: "$${HOME}/$$$${USER}"
+threads: many
+`), 0644)
+	assert.NoError(t, err)
+
+	_, err = NewConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 7:")
+
+	issues, err := validateConfig(path)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 1)
+	assert.Equal(t, 7, issues[0].Line)
+
+	// $$ is expanded exactly once
+	err = ioutil.WriteFile(path, []byte(`
+files:
+    "**.json":
+        pull:
+            format: "$${HOME}/$$$${USER}"
+`), 0644)
+	assert.NoError(t, err)
+
+	config, err := NewConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "${HOME}/$${USER}", config.Files["**.json"].Pull.Format)
+}
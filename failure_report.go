@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/reconquest/hierr-go"
+)
+
+// Failure describes single file or file locale, which command failed to
+// process.
+type Failure struct {
+	FileURI string
+	Locale  string
+	Path    string
+	Error   error
+}
+
+// FailureReport collects failures from concurrently running tasks, so command
+// can report all of them at once and exit with error.
+type FailureReport struct {
+	sync.Mutex
+
+	action   string
+	failFast bool
+	items    []Failure
+}
+
+func NewFailureReport(action string, failFast bool) *FailureReport {
+	return &FailureReport{
+		action:   action,
+		failFast: failFast,
+	}
+}
+
+func (report *FailureReport) Add(failure Failure) {
+	report.Lock()
+	defer report.Unlock()
+
+	logger.Debugf("%s failed: %s", report.action, failure.Error)
+
+	report.items = append(report.items, failure)
+}
+
+func (report *FailureReport) Len() int {
+	report.Lock()
+	defer report.Unlock()
+
+	return len(report.items)
+}
+
+// Stopped returns true if no new work should be started because of
+// --fail-fast mode.
+func (report *FailureReport) Stopped() bool {
+	return report.failFast && report.Len() > 0
+}
+
+func (report *FailureReport) Render(writer io.Writer) error {
+	report.Lock()
+	defer report.Unlock()
+
+	if len(report.items) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(writer, "\nFailed to %s:\n", report.action)
+
+	table := NewTableWriter(writer)
+
+	for _, failure := range report.items {
+		locale := failure.Locale
+		if locale == "" {
+			locale = "-"
+		}
+
+		path := failure.Path
+		if path == "" {
+			path = "-"
+		}
+
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%s\t%s\n",
+			failure.FileURI,
+			locale,
+			path,
+			compactError(failure.Error),
+		)
+	}
+
+	return RenderTable(table)
+}
+
+// Err returns error describing all failures or nil if there were none.
+func (report *FailureReport) Err() error {
+	count := report.Len()
+	if count == 0 {
+		return nil
+	}
+
+	return NewError(
+		fmt.Errorf("failed to %s %d file(s)", report.action, count),
+
+		`See summary above for failed files and reasons. Rerun command `+
+			`with -v option to get more details.`,
+	)
+}
+
+// compactError converts hierarchical error into single line, so it can fit
+// into table row.
+func compactError(err error) string {
+	var parts []string
+
+	branches := []string{
+		hierr.BranchDelimiterBox,
+		hierr.BranchChainerBox,
+		hierr.BranchDelimiterASCII,
+		hierr.BranchChainerASCII,
+	}
+
+	for _, line := range strings.Split(err.Error(), "\n") {
+		for trimmed := ""; trimmed != line; {
+			trimmed = line
+			line = strings.TrimSpace(line)
+
+			for _, branch := range branches {
+				line = strings.TrimPrefix(line, branch)
+			}
+		}
+
+		if line != "" {
+			parts = append(parts, line)
+		}
+	}
+
+	return strings.Join(parts, ": ")
+}
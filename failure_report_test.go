@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailureReportSummary(t *testing.T) {
+	report := NewFailureReport("download", false)
+
+	assert.NoError(t, report.Err())
+
+	report.Add(Failure{
+		FileURI: "/res/strings.json",
+		Locale:  "de-DE",
+		Path:    "res/de-DE.json",
+		Error:   errors.New("connection reset"),
+	})
+	report.Add(Failure{
+		FileURI: "/res/other.json",
+		Error:   errors.New("not found"),
+	})
+
+	assert.False(t, report.Stopped())
+
+	var buffer bytes.Buffer
+
+	assert.NoError(t, report.Render(&buffer))
+	assert.Equal(
+		t,
+		"\nFailed to download:\n"+
+			"/res/strings.json  de-DE  res/de-DE.json  connection reset\n"+
+			"/res/other.json    -      -               not found\n",
+		buffer.String(),
+	)
+
+	assert.EqualError(
+		t,
+		report.Err(),
+		"ERROR: failed to download 2 file(s)\n\n"+
+			"See summary above for failed files and reasons. Rerun command "+
+			"with -v option to get more details.",
+	)
+
+	report = NewFailureReport("download", true)
+	assert.False(t, report.Stopped())
+
+	report.Add(Failure{Error: errors.New("not found")})
+	assert.True(t, report.Stopped())
+}
+
+// setupTranslationUpdate creates project with config and translations of
+// single file for given locales and returns config and command arguments.
+func setupTranslationUpdate(
+	t *testing.T,
+	locales ...string,
+) (Config, map[string]interface{}) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, defaultConfigName)
+
+	err := ioutil.WriteFile(path, []byte(`
+project_id: project
+threads: 1
+files:
+    "/res/**":
+        pull:
+            format: "res/{{with .Locale}}{{.}}{{else}}source{{end}}.json"
+`), 0644)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.Mkdir(filepath.Join(directory, "res"), 0755))
+
+	for _, locale := range locales {
+		err := ioutil.WriteFile(
+			filepath.Join(directory, "res", locale+".json"),
+			[]byte(`{"key": "value"}`),
+			0644,
+		)
+		assert.NoError(t, err)
+	}
+
+	config, err := NewConfig(path)
+	assert.NoError(t, err)
+
+	args := map[string]interface{}{
+		"--post-translation": false,
+		"--overwrite":        false,
+		"--locale":           []string{},
+	}
+
+	return config, args
+}
+
+func TestTranslationUpdateReportsFailures(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.FailImports = true
+
+	config, args := setupTranslationUpdate(t, "de-DE", "fr-FR", "ja-JP")
+
+	err := doFilesTranslationUpdate(context.Background(), client, config, args)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to import 3 file(s)")
+	assert.Equal(t, []string{"de-DE", "fr-FR", "ja-JP"}, api.Imports())
+}
+
+func TestTranslationUpdateFailFast(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.FailImports = true
+
+	config, args := setupTranslationUpdate(t, "de-DE", "fr-FR", "ja-JP")
+	args["--fail-fast"] = true
+
+	err := doFilesTranslationUpdate(context.Background(), client, config, args)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to import 1 file(s)")
+	assert.Equal(t, []string{"de-DE"}, api.Imports())
+}
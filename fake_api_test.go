@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	smartling "github.com/Smartling/api-sdk-go"
+)
+
+// fakeAPI is minimal Smartling API server for testing commands, which
+// require concrete API client. It serves project with single file and
+// records all import requests.
+type fakeAPI struct {
+	sync.Mutex
+
+	// FailImports makes every import request fail.
+	FailImports bool
+
+	imports []string
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *smartling.Client) {
+	api := &fakeAPI{}
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client := smartling.NewClient("user", "secret")
+	client.BaseURL = server.URL
+
+	return api, client
+}
+
+// Imports returns locales, which translations were imported, in order of
+// requests.
+func (api *fakeAPI) Imports() []string {
+	api.Lock()
+	defer api.Unlock()
+
+	return append([]string{}, api.imports...)
+}
+
+func (api *fakeAPI) ServeHTTP(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+	var data interface{} = map[string]interface{}{}
+
+	path := request.URL.Path
+
+	switch {
+	case strings.HasSuffix(path, "/authenticate"):
+		data = map[string]interface{}{
+			"accessToken": "token",
+			"expiresIn":   3600,
+		}
+
+	case strings.HasSuffix(path, "/files/list"):
+		data = map[string]interface{}{
+			"totalCount": 1,
+			"items": []map[string]interface{}{{
+				"fileUri":      "/res/strings.json",
+				"fileType":     "json",
+				"lastUploaded": "2020-01-01T00:00:00Z",
+			}},
+		}
+
+	case strings.HasSuffix(path, "/file/import"):
+		// path is /files-api/v2/projects/<project>/locales/<locale>/...
+		locale := strings.Split(path, "/")[6]
+
+		api.Lock()
+		api.imports = append(api.imports, locale)
+		api.Unlock()
+
+		if api.FailImports {
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(
+				writer,
+				`{"response": {"code": "VALIDATION_ERROR", "errors": []}}`,
+			)
+
+			return
+		}
+
+		data = map[string]interface{}{
+			"stringCount": 1,
+			"wordCount":   1,
+		}
+
+	case strings.HasPrefix(path, "/projects-api/"):
+		data = map[string]interface{}{
+			"projectId":      "project",
+			"sourceLocaleId": "en-US",
+			"targetLocales": []map[string]interface{}{
+				{"localeId": "de-DE", "enabled": true},
+				{"localeId": "fr-FR", "enabled": true},
+				{"localeId": "ja-JP", "enabled": true},
+			},
+		}
+	}
+
+	contents, _ := json.Marshal(map[string]interface{}{
+		"response": map[string]interface{}{
+			"code": "SUCCESS",
+			"data": data,
+		},
+	})
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(contents)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	smartling "github.com/Smartling/api-sdk-go"
+)
+
+// FormatData is passed to file name format templates. The same data is used
+// by pull, status and upload-translation commands, so single format from
+// config file works with all of them.
+type FormatData struct {
+	// FileURI is file URI without branch prefix.
+	FileURI string
+
+	// BranchFileURI is original file URI in Smartling, including branch
+	// prefix.
+	BranchFileURI string
+
+	// Branch is branch name specified by --branch option.
+	Branch string
+
+	// Locale is Smartling locale ID, empty for source file.
+	Locale string
+
+	// AppLocale is application locale from locales mapping.
+	AppLocale string
+
+	SourceLocale string
+	ProjectID    string
+	FileType     string
+	LastUploaded time.Time
+}
+
+// NewFormatData returns format data for given file without locale, which
+// should be set separately for every translation.
+func NewFormatData(
+	config Config,
+	file smartling.File,
+	branch string,
+	sourceLocale string,
+) FormatData {
+	branch = strings.TrimSuffix(branch, "/")
+
+	uri := file.FileURI
+	if branch != "" {
+		uri = strings.TrimPrefix(uri, branch+"/")
+	}
+
+	return FormatData{
+		FileURI:       uri,
+		BranchFileURI: file.FileURI,
+		Branch:        branch,
+		SourceLocale:  sourceLocale,
+		ProjectID:     config.ProjectID,
+		FileType:      string(file.FileType),
+		LastUploaded:  file.LastUploaded.Time,
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteFileFormatWithFormatData(t *testing.T) {
+	var section FileConfig
+
+	section.Pull.Format = "{{.Branch}}/{{.AppLocale}}/{{base .FileURI}}" +
+		"{{if ne .BranchFileURI .FileURI}}!{{end}}"
+
+	config := Config{
+		ProjectID: "project",
+		Locales: []LocaleConfig{
+			{Application: "de", Smartling: "de-DE"},
+		},
+		Files: map[string]FileConfig{
+			"res/**": section,
+		},
+	}
+
+	config.buildLocaleMaps()
+
+	file := smartling.File{
+		FileURI:  "feature/res/strings.xml",
+		FileType: "android",
+	}
+	file.LastUploaded.Time = time.Now()
+
+	data := NewFormatData(config, file, "feature/", "en-US")
+
+	assert.Equal(t, "res/strings.xml", data.FileURI)
+	assert.Equal(t, "feature/res/strings.xml", data.BranchFileURI)
+	assert.Equal(t, "en-US", data.SourceLocale)
+	assert.Equal(t, "project", data.ProjectID)
+	assert.Equal(t, "android", data.FileType)
+
+	data.Locale = "de-DE"
+	data.AppLocale = config.GetAppLocale(section, data.Locale)
+
+	path, err := executeFileFormat(config, "", usePullFormat, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "feature/de/strings.xml!", path)
+}
@@ -13,7 +13,32 @@ import (
 )
 
 func globFilesRemote(
-	client *smartling.Client,
+	client smartling.ClientInterface,
+	project string,
+	uri string,
+) ([]smartling.File, error) {
+	result, err := listFilesRemote(client, project, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, NewError(
+			fmt.Errorf(
+				"no files found on the remote server matching provided pattern",
+			),
+
+			"Check that file URI pattern is correct.",
+		)
+	}
+
+	return result, nil
+}
+
+// listFilesRemote works like globFilesRemote, but doesn't treat empty list as
+// an error.
+func listFilesRemote(
+	client smartling.ClientInterface,
 	project string,
 	uri string,
 ) ([]smartling.File, error) {
@@ -53,16 +78,6 @@ func globFilesRemote(
 		}
 	}
 
-	if len(result) == 0 {
-		return nil, NewError(
-			fmt.Errorf(
-				"no files found on the remote server matching provided pattern",
-			),
-
-			"Check that file URI pattern is correct.",
-		)
-	}
-
 	return result, nil
 }
 
@@ -78,6 +93,23 @@ func getDirectoryFromPattern(mask string) (string, string) {
 	return matches[1], matches[2]
 }
 
+// getConfigFilePattern returns local pattern for pattern from files section
+// of config. Patterns, which start with /, are matched against file URIs,
+// which are relative to directory with config file, so they are resolved
+// against that directory, unless they already point inside it.
+func getConfigFilePattern(base string, pattern string) string {
+	if !strings.HasPrefix(pattern, "/") {
+		return pattern
+	}
+
+	directory, _ := getDirectoryFromPattern(pattern)
+	if directory == base || strings.HasPrefix(directory, base+"/") {
+		return pattern
+	}
+
+	return base + pattern
+}
+
 func globFilesLocallyFunc(
 	directory string,
 	base string,
@@ -7,7 +7,6 @@ require (
 	github.com/Smartling/api-sdk-go v0.0.0-20200428111932-35139033a212
 	github.com/docopt/docopt-go v0.0.0-20180111231733-ee0de3bc6815
 	github.com/gobwas/glob v0.2.3
-	github.com/imdario/mergo v0.3.11
 	github.com/kovetskiy/ko v0.0.0-20200620085804-ec6b220882b0
 	github.com/kovetskiy/lorg v0.0.0-20200107130803-9a7136a95634
 	github.com/reconquest/hierr-go v0.0.0-20170824213838-7d09c0176fd2
@@ -17,6 +16,7 @@ require (
 	golang.org/x/crypto v0.0.0-20200820211705-5c72a883971a // indirect
 	golang.org/x/time v0.0.0-20200630173020-3af7569d3a1e
 	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
 
 replace github.com/Smartling/api-sdk-go => github.com/cuyl/api-sdk-go v0.0.0-20201015021753-b978e930d84f
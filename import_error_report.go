@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/reconquest/hierr-go"
+)
+
+// ImportErrorReport collects translation import errors, which are returned
+// by Smartling API for successfully imported files, from concurrently
+// running imports.
+type ImportErrorReport struct {
+	sync.Mutex
+
+	// Path is a file to write report to, format is chosen by extension:
+	// CSV for .csv and JSON otherwise.
+	Path string
+
+	// Max is a number of import errors, which is allowed before command
+	// fails. Negative value means no limit.
+	Max int
+
+	items []ImportErrorRecord
+}
+
+func NewImportErrorReport(
+	args map[string]interface{},
+) (*ImportErrorReport, error) {
+	var (
+		path, _ = args["--import-errors"].(string)
+		max, _  = args["--max-import-errors"].(string)
+	)
+
+	report := &ImportErrorReport{
+		Path: path,
+		Max:  -1,
+	}
+
+	if max != "" {
+		var err error
+
+		report.Max, err = strconv.Atoi(max)
+		if err != nil || report.Max < 0 {
+			return nil, InvalidConfigValueError{
+				ValueName:   "--max-import-errors",
+				Description: "should be non-negative integer number",
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Add records import errors from import result of given local file.
+func (report *ImportErrorReport) Add(
+	file string,
+	uri string,
+	locale string,
+	result *smartling.FileImportResult,
+) {
+	report.Lock()
+	defer report.Unlock()
+
+	for _, item := range result.TranslationImportErrors {
+		logger.Warningf(
+			"[%s] key: %s messages: %v hash: %s",
+			file,
+			item.ImportKey,
+			item.Messages,
+			item.StringHashcode,
+		)
+
+		report.items = append(report.items, ImportErrorRecord{
+			File:      file,
+			FileURI:   uri,
+			Locale:    locale,
+			ImportKey: item.ImportKey,
+			Hashcode:  item.StringHashcode,
+			Messages:  item.Messages,
+		})
+	}
+}
+
+func (report *ImportErrorReport) Len() int {
+	report.Lock()
+	defer report.Unlock()
+
+	return len(report.items)
+}
+
+// Write writes collected import errors into report file. Report is written
+// even if there are no errors, so stale report is not left from previous run.
+func (report *ImportErrorReport) Write() error {
+	if report.Path == "" {
+		return nil
+	}
+
+	report.Lock()
+	defer report.Unlock()
+
+	file, err := os.Create(report.Path)
+	if err != nil {
+		return hierr.Errorf(
+			err,
+			`unable to create import errors report "%s"`,
+			report.Path,
+		)
+	}
+
+	defer file.Close()
+
+	output := &Output{
+		Mode:   outputModeJSON,
+		writer: file,
+	}
+
+	if strings.EqualFold(filepath.Ext(report.Path), ".csv") {
+		output.Mode = outputModeCSV
+	}
+
+	for _, item := range report.items {
+		output.Add(item)
+	}
+
+	err = output.Flush()
+	if err != nil {
+		return hierr.Errorf(
+			err,
+			`unable to write import errors report "%s"`,
+			report.Path,
+		)
+	}
+
+	return file.Close()
+}
+
+// Err returns error if number of import errors exceeds --max-import-errors.
+func (report *ImportErrorReport) Err() error {
+	count := report.Len()
+	if report.Max < 0 || count <= report.Max {
+		return nil
+	}
+
+	return NewError(
+		fmt.Errorf(
+			"%d translation import error(s) found, at most %d allowed",
+			count,
+			report.Max,
+		),
+
+		`Fix translations listed in warnings above or in report specified `+
+			`by --import-errors option and import them again.`,
+	)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportErrorReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.csv")
+
+	report, err := NewImportErrorReport(map[string]interface{}{
+		"--import-errors":     path,
+		"--max-import-errors": "1",
+	})
+	assert.NoError(t, err)
+
+	var result smartling.FileImportResult
+
+	result.TranslationImportErrors = make(
+		[]struct {
+			FileURI        string
+			ImportKey      string
+			Messages       []string
+			StringHashcode string
+		},
+		1,
+	)
+
+	result.TranslationImportErrors[0].ImportKey = "key"
+	result.TranslationImportErrors[0].StringHashcode = "hash"
+	result.TranslationImportErrors[0].Messages = []string{"a", "b"}
+
+	report.Add("de.json", "/strings.json", "de-DE", &result)
+	assert.NoError(t, report.Err())
+
+	report.Add("fr.json", "/strings.json", "fr-FR", &result)
+	assert.Error(t, report.Err())
+
+	assert.NoError(t, report.Write())
+
+	contents, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"file,file_uri,locale,import_key,hashcode,messages\n"+
+			"de.json,/strings.json,de-DE,key,hash,a;b\n"+
+			"fr.json,/strings.json,fr-FR,key,hash,a;b\n",
+		string(contents),
+	)
+
+	_, err = NewImportErrorReport(map[string]interface{}{
+		"--max-import-errors": "-1",
+	})
+	assert.Error(t, err)
+}
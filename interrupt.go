@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// abortTimeout is time given to requests in progress to finish after
+// program is interrupted.
+const abortTimeout = 10 * time.Second
+
+var errInterrupted = fmt.Errorf("interrupted")
+
+// handleSignals returns context which is canceled on first SIGINT or
+// SIGTERM, so commands stop scheduling new work. Second signal terminates
+// program immediately.
+func handleSignals() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+
+		logger.Warningf(
+			"interrupted, waiting up to %s for requests in progress "+
+				"(interrupt again to terminate immediately)",
+			abortTimeout,
+		)
+
+		cancel()
+
+		<-signals
+
+		os.Exit(130)
+	}()
+
+	return ctx
+}
+
+// withGracePeriod returns context which is canceled when specified period
+// passes after parent context is done. Returned cancel function should be
+// called when command finishes to release resources, since parent context is
+// not done in that case.
+func withGracePeriod(
+	parent context.Context,
+	period time.Duration,
+) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if parent.Done() == nil {
+		return ctx, cancel
+	}
+
+	go func() {
+		select {
+		case <-parent.Done():
+		case <-ctx.Done():
+			return
+		}
+
+		timer := time.NewTimer(period)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// AbortTransport binds every request to context, so requests in progress
+// are aborted when context is done. Request own context, like deadline set
+// by caller, is still respected.
+type AbortTransport struct {
+	Transport http.RoundTripper
+	Context   context.Context
+}
+
+func (transport *AbortTransport) RoundTrip(
+	request *http.Request,
+) (*http.Response, error) {
+	if transport.Context.Done() == nil {
+		return transport.Transport.RoundTrip(request)
+	}
+
+	ctx, cancel := context.WithCancel(request.Context())
+
+	// done is closed when request is complete, so goroutine exits even if
+	// neither context is ever done
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-transport.Context.Done():
+			cancel()
+		case <-ctx.Done():
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+
+	release := func() {
+		once.Do(func() {
+			close(done)
+			cancel()
+		})
+	}
+
+	response, err := transport.Transport.RoundTrip(request.WithContext(ctx))
+	if err != nil {
+		release()
+
+		return nil, err
+	}
+
+	// context should live until response body is read, otherwise reading
+	// will fail, so it's released only when body is read till the end or
+	// closed
+	response.Body = &cancelReadCloser{
+		ReadCloser: response.Body,
+		cancel:     release,
+	}
+
+	return response, nil
+}
+
+type cancelReadCloser struct {
+	io.ReadCloser
+
+	cancel func()
+}
+
+func (body *cancelReadCloser) Read(data []byte) (int, error) {
+	size, err := body.ReadCloser.Read(data)
+	if err == io.EOF {
+		body.cancel()
+	}
+
+	return size, err
+}
+
+func (body *cancelReadCloser) Close() error {
+	defer body.cancel()
+
+	return body.ReadCloser.Close()
+}
+
+func newInterruptedError(action string, completed int, total int) error {
+	return NewError(
+		fmt.Errorf(
+			"interrupted, %d of %d file(s) were %s",
+			completed,
+			total,
+			action,
+		),
+
+		`Run command again to process remaining files.`,
+	)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbortTransportKeepsRequestContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			select {
+			case <-request.Context().Done():
+			case <-time.After(time.Second):
+			}
+		},
+	))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := http.Client{
+		Transport: &AbortTransport{
+			Transport: http.DefaultTransport,
+			Context:   ctx,
+		},
+	}
+
+	// deadline of request itself should abort it
+	deadline, stop := context.WithTimeout(
+		context.Background(),
+		10*time.Millisecond,
+	)
+	defer stop()
+
+	request, err := http.NewRequestWithContext(deadline, "GET", server.URL, nil)
+	assert.NoError(t, err)
+
+	_, err = client.Do(request)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// as well as transport context
+	request, err = http.NewRequest("GET", server.URL, nil)
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err = client.Do(request)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestAbortTransportKeepsBodyReadable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			writer.Write([]byte("contents"))
+		},
+	))
+	defer server.Close()
+
+	client := http.Client{
+		Transport: &AbortTransport{
+			Transport: http.DefaultTransport,
+			Context:   context.Background(),
+		},
+	}
+
+	response, err := client.Get(server.URL)
+	assert.NoError(t, err)
+
+	contents, err := ioutil.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "contents", string(contents))
+	assert.NoError(t, response.Body.Close())
+}
+
+// transportFunc allows to use function, which can fail, as
+// http.RoundTripper.
+type transportFunc func(request *http.Request) (*http.Response, error)
+
+func (function transportFunc) RoundTrip(
+	request *http.Request,
+) (*http.Response, error) {
+	return function(request)
+}
+
+func TestAbortTransportReleasesRequestContext(t *testing.T) {
+	var requestContext context.Context
+
+	transport := &AbortTransport{
+		Transport: transportFunc(
+			func(request *http.Request) (*http.Response, error) {
+				requestContext = request.Context()
+
+				if request.Method == "POST" {
+					return nil, errors.New("connection refused")
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body: ioutil.NopCloser(
+						strings.NewReader("contents"),
+					),
+				}, nil
+			},
+		),
+		Context: context.Background(),
+	}
+
+	// context, which is never done, doesn't need to be watched
+	request, err := http.NewRequest("GET", "http://localhost/", nil)
+	assert.NoError(t, err)
+
+	_, err = transport.RoundTrip(request)
+	assert.NoError(t, err)
+	assert.Equal(t, request.Context(), requestContext)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	transport.Context = ctx
+
+	// body read till the end releases request even if it's not closed
+	response, err := transport.RoundTrip(request)
+	assert.NoError(t, err)
+	assert.NoError(t, requestContext.Err())
+
+	contents, err := ioutil.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "contents", string(contents))
+	assert.Error(t, requestContext.Err())
+
+	// as well as closed body, which is not read
+	response, err = transport.RoundTrip(request)
+	assert.NoError(t, err)
+	assert.NoError(t, requestContext.Err())
+
+	assert.NoError(t, response.Body.Close())
+	assert.NoError(t, response.Body.Close())
+	assert.Error(t, requestContext.Err())
+
+	// and failed request
+	request.Method = "POST"
+
+	_, err = transport.RoundTrip(request)
+	assert.Error(t, err)
+	assert.Error(t, requestContext.Err())
+}
+
+func TestWithGracePeriod(t *testing.T) {
+	parent, interrupt := context.WithCancel(context.Background())
+
+	ctx, stop := withGracePeriod(parent, 20*time.Millisecond)
+	defer stop()
+
+	interrupt()
+
+	assert.NoError(t, ctx.Err())
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		assert.Fail(t, "context is not canceled after grace period")
+	}
+
+	// command, which finished without interrupt, releases context
+	parent, interrupt = context.WithCancel(context.Background())
+	defer interrupt()
+
+	ctx, stop = withGracePeriod(parent, time.Hour)
+
+	stop()
+
+	assert.Error(t, ctx.Err())
+	assert.NoError(t, parent.Err())
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Locale is a locale ID split into BCP 47 subtags, e.g. "sr-Latn-RS" is
+// split into language "sr", script "Latn" and region "RS".
+type Locale struct {
+	Language string
+	Script   string
+	Region   string
+}
+
+// parseLocale splits locale ID, which subtags are separated either by - or
+// _, into language, script and region. Variants and extensions are ignored.
+func parseLocale(id string) Locale {
+	var locale Locale
+
+	parts := strings.FieldsFunc(id, func(char rune) bool {
+		return char == '-' || char == '_'
+	})
+
+	for index, part := range parts {
+		switch {
+		case index == 0:
+			locale.Language = strings.ToLower(part)
+
+		case len(part) == 4 && isLetters(part) && locale.Script == "" &&
+			locale.Region == "":
+			locale.Script = strings.ToUpper(part[:1]) +
+				strings.ToLower(part[1:])
+
+		case (len(part) == 2 && isLetters(part) || len(part) == 3 &&
+			isDigits(part)) && locale.Region == "":
+			locale.Region = strings.ToUpper(part)
+		}
+	}
+
+	return locale
+}
+
+// POSIX returns locale in POSIX style: pt_BR or sr_RS@latin.
+func (locale Locale) POSIX() string {
+	result := locale.join("_", locale.Language, locale.Region)
+
+	if locale.Script != "" {
+		modifier, ok := posixScripts[locale.Script]
+		if !ok {
+			modifier = strings.ToLower(locale.Script)
+		}
+
+		result += "@" + modifier
+	}
+
+	return result
+}
+
+// Android returns locale as Android resource qualifier: pt-rBR or
+// b+sr+Latn+RS when script or numeric region is specified, because -r
+// qualifier accepts only two-letter regions.
+func (locale Locale) Android() string {
+	if locale.Script != "" || len(locale.Region) > 2 {
+		return locale.join(
+			"+", "b", locale.Language, locale.Script, locale.Region,
+		)
+	}
+
+	if locale.Region == "" {
+		return locale.Language
+	}
+
+	return locale.Language + "-r" + locale.Region
+}
+
+// IOS returns locale as iOS localization (.lproj) name: pt-BR or zh-Hans.
+func (locale Locale) IOS() string {
+	return locale.join("-", locale.Language, locale.Script, locale.Region)
+}
+
+// Java returns locale as used in Java resource bundle names: pt_BR or
+// sr_Latn_RS.
+func (locale Locale) Java() string {
+	return locale.join("_", locale.Language, locale.Script, locale.Region)
+}
+
+func (locale Locale) join(separator string, parts ...string) string {
+	if locale.Language == "" {
+		return ""
+	}
+
+	var result []string
+
+	for _, part := range parts {
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+
+	return strings.Join(result, separator)
+}
+
+// posixScripts maps ISO 15924 script codes to POSIX locale modifiers, which
+// differ from lowercased script code.
+var posixScripts = map[string]string{
+	"Latn": "latin",
+	"Cyrl": "cyrillic",
+	"Arab": "arabic",
+	"Deva": "devanagari",
+}
+
+func isLetters(value string) bool {
+	for _, char := range value {
+		if char > unicode.MaxASCII || !unicode.IsLetter(char) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDigits(value string) bool {
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return true
+}
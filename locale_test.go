@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleFormats(t *testing.T) {
+	testcases := []struct {
+		id      string
+		posix   string
+		android string
+		ios     string
+		java    string
+	}{
+		{"", "", "", "", ""},
+		{"de", "de", "de", "de", "de"},
+		{"pt-BR", "pt_BR", "pt-rBR", "pt-BR", "pt_BR"},
+		{"zh-Hans", "zh@hans", "b+zh+Hans", "zh-Hans", "zh_Hans"},
+		{"sr-latn-rs", "sr_RS@latin", "b+sr+Latn+RS", "sr-Latn-RS", "sr_Latn_RS"},
+		{"es_419", "es_419", "b+es+419", "es-419", "es_419"},
+	}
+
+	for _, testcase := range testcases {
+		locale := parseLocale(testcase.id)
+
+		assert.Equal(t, testcase.posix, locale.POSIX(), testcase.id)
+		assert.Equal(t, testcase.android, locale.Android(), testcase.id)
+		assert.Equal(t, testcase.ios, locale.IOS(), testcase.id)
+		assert.Equal(t, testcase.java, locale.Java(), testcase.id)
+	}
+}
+
+func TestCompileFormatFunctions(t *testing.T) {
+	format, err := compileFormat(
+		`{{dir .FileURI | trimPrefix "/"}}/values-{{android .Locale}}/` +
+			`{{base .FileURI | replace ".xml" "" | upper}}` +
+			`-{{date "2006-01-02" .LastUploaded}}`,
+	)
+	assert.NoError(t, err)
+
+	result, err := format.Execute(map[string]interface{}{
+		"FileURI":      "/res/values/strings.xml",
+		"Locale":       "sr-Latn-RS",
+		"LastUploaded": time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "res/values/values-b+sr+Latn+RS/STRINGS-2020-05-01", result)
+}
+
+func TestCompileFormatDateOfRemoteFile(t *testing.T) {
+	format, err := compileFormat(
+		`{{.FileURI}}\t{{date "2006-01-02 15:04" .LastUploaded}}\n`,
+	)
+	assert.NoError(t, err)
+
+	result, err := format.Execute(smartling.File{
+		FileURI: "/res/values/strings.xml",
+		LastUploaded: smartling.UTC{
+			Time: time.Date(2020, 5, 1, 12, 30, 0, 0, time.UTC),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/res/values/strings.xml\t2020-05-01 12:30\n", result)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
@@ -24,35 +25,53 @@ Add --help option to command to get detailed help for specific command.
 
 Usage:
   smartling-cli [options] [-v]... init --help
-  smartling-cli [options] [-v]... init [--dry-run]
+  smartling-cli [options] [-v]... init
+  smartling-cli [options] [-v]... config validate --help
+  smartling-cli [options] [-v]... config validate
+  smartling-cli [options] [-v]... config show --help
+  smartling-cli [options] [-v]... config show [<uri>]
   smartling-cli [options] [-v]... projects list --help
   smartling-cli [options] [-v]... projects list [--short]
   smartling-cli [options] [-v]... projects info --help
   smartling-cli [options] [-v]... projects info
   smartling-cli [options] [-v]... projects locales --help
   smartling-cli [options] [-v]... projects locales [--source] [--short] [--format=]
+  smartling-cli [options] [-v]... projects locales add --help
+  smartling-cli [options] [-v]... projects locales add <locale-id>...
+  smartling-cli [options] [-v]... projects locales remove --help
+  smartling-cli [options] [-v]... projects locales remove <locale-id>...
+  smartling-cli [options] [-v]... projects locales sync --help
+  smartling-cli [options] [-v]... projects locales sync
   smartling-cli [options] [-v]... files list --help
   smartling-cli [options] [-v]... files list [--format=] [--short] [<uri>]
   smartling-cli [options] [-v]... files (pull|get) --help
   smartling-cli [options] [-v]... files (pull|get) [--locale=]... [--directory=] [--source] [--format=] [--branch=]
-                                               [--progress=] [--retrieve=] [<uri>]
+                                               [--progress=] [--retrieve=] [--full] [--fail-fast]
+                                               [<uri>]
   smartling-cli [options] [-v]... files push --help
   smartling-cli [options] [-v]... files push [(--authorize|--locale=...)] [--branch=] [--type=]
-                                         [--directory=] [--directive=]... [<file>] [<uri>]
+                                         [--directory=] [--directive=]... [--force]
+                                         [--prune [--prune-exclude=]... [--yes]]
+                                         [<file>] [<uri>]
   smartling-cli [options] [-v]... files rename --help
   smartling-cli [options] [-v]... files rename <old-uri> <new-uri>
   smartling-cli [options] [-v]... files status --help
-  smartling-cli [options] [-v]... files status [--directory=] [--format=] [<uri>]
+  smartling-cli [options] [-v]... files status [--directory=] [--format=]
+                                           [--min-progress=]... [--min-file-progress=]
+                                           [--require-locale=]... [--junit=] [<uri>]
   smartling-cli [options] [-v]... files delete --help
   smartling-cli [options] [-v]... files delete <uri>
   smartling-cli [options] [-v]... files import --help
 	smartling-cli [options] [-v]... files import <uri> <file> <locale>
                                            [(--published|--post-translation)]
                                            [--type=] [--overwrite]
-  smartling-cli [options] [-v]... files import --help
-	smartling-cli [options] [-v]... files upload-translation [uri]
+                                           [--import-errors=] [--max-import-errors=]
+  smartling-cli [options] [-v]... files upload-translation --help
+  smartling-cli [options] [-v]... files upload-translation [--locale=]... [--since=]
                                            [(--published|--post-translation)] [--branch=]
-                                           [--type=] [--overwrite] [--source-locale=] 
+                                           [--type=] [--overwrite] [--source-locale=]
+                                           [--fail-fast] [--import-errors=]
+                                           [--max-import-errors=] [<uri>]
   smartling-cli --help
 
 Commands:
@@ -61,6 +80,10 @@ Commands:
                            configuration file.
    --dry-run              Do not actually write file, just output it
                            on stdout.
+  config                  Used to inspect configuration file.
+   validate               Checks config file without connecting to API.
+   show <uri>             Shows effective config values and their origins,
+                           and files section values for given file.
   projects                Used to access various project sub-commands.
    list                   Lists projects for current account.
     -s --short            Display only project IDs.
@@ -69,12 +92,25 @@ Commands:
     -s --short            Display only target locale IDs.
     --format <format>     Use specified format for listing locales.
                            [format: $PROJECTS_LOCALES_FORMAT]
+    add <locale-id>...    Add target locales to project.
+    remove <locale-id>... Remove target locales from project.
+    sync                  Add locales from config file, which are missing in
+                           project.
   files                   Used to access various files sub-commands.
    status <uri>           Shows file translation status.
     --format <format>     Specifies format to use for file status output.
                            [default: $FILE_STATUS_FORMAT]
     --directory <dir>     Use another directory as reference to check for
                            local files.
+    --min-progress <[locale=]percent>
+                          Fail if translation progress is below specified
+                           percent; can be limited to specific locale.
+    --min-file-progress <percent>
+                          Fail if file progress across all locales is below
+                           specified percent.
+    --require-locale <locale>
+                          Fail if file is not translated into specified locale.
+    --junit <path>        Write threshold checks as JUnit XML report.
    list <uri>             Lists files from specified project.
     -s --short            Output only file URI.
     --format <format>     Specifies format to use for file list output.
@@ -86,6 +122,9 @@ Commands:
                            percent of work complete.
     --retrieve <type>     Retrieval type: pending, published, pseudo
                            or contextMatchingInstrumented.
+    --full                Download all files, even if they were not
+                           modified since last pull.
+    --fail-fast           Stop downloading on the first error.
     -d --directory <dir>  Download all files to specified directory.
     --format <format>     Can be used to format path to downloaded files.
                            Note, that single file can be translated in
@@ -101,6 +140,13 @@ Commands:
                            automatically deduced from extension.
     -r --directive <dir>  Specifies one or more directives to use in push
                            request.
+    --force               Upload files even if they are not changed since
+                           last push.
+    --prune               Delete remote files under the same branch and
+                           patterns, which have no local counterpart.
+    --prune-exclude <uri> Never prune remote files matching specified
+                           pattern. Can be specified several times.
+    --yes                 Do not ask for confirmation before pruning.
    rename <old> <new>     Renames given file by old URI into new URI.
    delete <uri>           Deletes given file from Smartling. This operation
                            can not be undone, so use with care.
@@ -114,6 +160,12 @@ Commands:
     --type <type>         Specify file type. If option is not given, file type
                            will be deduced from extension.
     --overwrite           Overwrite any existing translations.
+    --import-errors <path>
+                          Write translation import errors into JSON report
+                           or CSV report if path ends with .csv.
+    --max-import-errors <number>
+                          Fail if more than <number> translation import
+                           errors are found.
    upload-translation <uri>   Upload matched files translations
     -b --branch <branch>  Prepend specified text to the file uri.
     --published           Translated content will be published.
@@ -121,6 +173,20 @@ Commands:
                            of translation. If there are none, it will be
                            published.
     --overwrite           Overwrite any existing translations.
+    --fail-fast           Stop importing on the first error.
+    -l --locale <locale>  Import only specified locales, either Smartling or
+                           application locale IDs.
+    --since <time>        Import only local files modified since specified
+                           date or duration, e.g. 2020-05-01 or 24h.
+    --source-locale <locale>
+                          Import local source-language files into specified
+                           locale.
+    --import-errors <path>
+                          Write translation import errors into JSON report
+                           or CSV report if path ends with .csv.
+    --max-import-errors <number>
+                          Fail if more than <number> translation import
+                           errors are found.
 
 
 Options:
@@ -129,6 +195,9 @@ Options:
                            By default CLI will look for file named
                            "smartling.yml" in current directory and in all
                            intermediate parents, emulating git behavior.
+  --profile <name>        Use named profile from "profiles" section of config
+                           file. Can be set by SMARTLING_PROFILE environment
+                           variable as well.
   -p --project <project>  Project ID to operate on.
                            This option overrides config value "project_id".
   -a --account <account>  Account ID to operate on.
@@ -149,10 +218,23 @@ Options:
   -t --type <type>        Specify file type. Depends on command.
   -r --directive <dir>    Directives to add to push request in form of
                            <name>=<value>.
-  --dry-run               Do not actually perform action, just log it.
+  -o --output <mode>      Output mode: table, json, yaml or csv. Structured
+                           modes are supported by projects and files commands.
+                           Run command with --help to see output fields.
+                           [default: table]
+  --dry-run               Do not actually perform action, just output
+                           what would be done. Supported by init, files push,
+                           delete, rename, import, upload-translation and
+                           projects locales add, remove and sync.
   --threads <number>      If command can be executed concurrently, it will be
                            executed for at most <number> of threads.
                            [default: 4]
+  --retries <number>      Retry failed API requests at most <number> of times,
+                           3 by default. Set to 0 to disable retries.
+                           This option overrides config value
+                           "retry.attempts". Requests, which server asks
+                           to retry later than "retry.max_delay", are not
+                           retried.
   -k --insecure           Skip HTTPS certificate validation.
   --proxy <url>           Use specified URL as proxy server.
   --smartling-url <url>   Specify base Smartling URL, merely for testing
@@ -226,6 +308,22 @@ func main() {
 	logger.SetFormat(lorg.NewFormat("* ${time} ${level:[%s]:right} %s"))
 	logger.SetIndentLines(true)
 
+	// config is validated without loading, because loading stops on first
+	// error
+	if args["config"].(bool) && args["validate"].(bool) {
+		path, err := getConfigPath(args)
+		if err == nil {
+			err = doConfigValidate(path)
+		}
+
+		if err != nil {
+			reportError(err)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
 	config, err := loadConfig(args)
 	if err != nil {
 		fmt.Println(err)
@@ -233,15 +331,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx := handleSignals()
+
 	switch {
 	case args["init"].(bool):
 		err = doInit(config, args)
 
+	case args["config"].(bool):
+		err = doConfigShow(config, args)
+
 	case args["projects"].(bool):
-		err = doProjects(config, args)
+		err = doProjects(ctx, config, args)
 
 	case args["files"].(bool):
-		err = doFiles(config, args)
+		err = doFiles(ctx, config, args)
 
 	default:
 		showHelp(args)
@@ -300,7 +403,7 @@ func findConfig(name string) (string, error) {
 	)
 }
 
-func loadConfig(args map[string]interface{}) (Config, error) {
+func getConfigPath(args map[string]interface{}) (string, error) {
 	var (
 		directory, _ = args["--directory"].(string)
 	)
@@ -314,7 +417,7 @@ func loadConfig(args map[string]interface{}) (Config, error) {
 		)
 		if err != nil {
 			if !args["init"].(bool) {
-				return Config{}, NewError(
+				return "", NewError(
 					err,
 
 					`Ensure, that config file exists either in the current `+
@@ -326,43 +429,97 @@ func loadConfig(args map[string]interface{}) (Config, error) {
 		}
 	}
 
+	return path, nil
+}
+
+func loadConfig(args map[string]interface{}) (Config, error) {
+	path, err := getConfigPath(args)
+	if err != nil {
+		return Config{}, err
+	}
+
 	config, err := NewConfig(path)
 	if err != nil {
+		if _, ok := err.(MissingConfigVariableError); ok {
+			return config, err
+		}
+
 		return config, NewError(
 			hierr.Errorf(err, `failed to load configuration file "%s".`, path),
 			`Check configuration file contents according to documentation.`,
 		)
 	}
 
-	if config.UserID == "" {
+	profile, _ := args["--profile"].(string)
+	if profile == "" {
+		profile = os.Getenv("SMARTLING_PROFILE")
+	}
+
+	if profile != "" {
+		err = config.UseProfile(profile)
+		if err != nil {
+			return config, err
+		}
+	}
+
+	// external secret source is not needed when secret is passed
+	// explicitly; config commands only display where secret comes from, so
+	// secret command is not run for them
+	if args["--secret"] == nil {
+		if args["config"].(bool) && config.getSecretSource() == "secret_command" {
+			config.setOrigin("secret", "secret_command (not run)")
+		} else {
+			config.secretError = resolveSecret(&config)
+		}
+	}
+
+	if config.UserID == "" && os.Getenv("SMARTLING_USER_ID") != "" {
 		config.UserID = os.Getenv("SMARTLING_USER_ID")
+		config.setOrigin("user_id", "$SMARTLING_USER_ID")
 	}
 
-	if config.Secret == "" {
+	if config.Secret == "" && os.Getenv("SMARTLING_SECRET") != "" {
 		config.Secret = os.Getenv("SMARTLING_SECRET")
+		config.setOrigin("secret", "$SMARTLING_SECRET")
 	}
 
-	if config.ProjectID == "" {
+	if config.ProjectID == "" && os.Getenv("SMARTLING_PROJECT_ID") != "" {
 		config.ProjectID = os.Getenv("SMARTLING_PROJECT_ID")
+		config.setOrigin("project_id", "$SMARTLING_PROJECT_ID")
 	}
 
 	if args["--user"] != nil {
 		config.UserID = args["--user"].(string)
+		config.setOrigin("user_id", "--user option")
 	}
 
 	if args["--secret"] != nil {
 		config.Secret = args["--secret"].(string)
+		config.setOrigin("secret", "--secret option")
 	}
 
 	if args["--account"] != nil {
 		config.AccountID = args["--account"].(string)
+		config.setOrigin("account_id", "--account option")
 	}
 
 	if args["--project"] != nil {
 		config.ProjectID = args["--project"].(string)
+		config.setOrigin("project_id", "--project option")
 	}
 
-	if !args["init"].(bool) {
+	if args["--proxy"] != nil {
+		config.Proxy = args["--proxy"].(string)
+		config.setOrigin("proxy", "--proxy option")
+	}
+
+	if args["--smartling-url"] != nil {
+		config.SmartlingURL = args["--smartling-url"].(string)
+		config.setOrigin("smartling_url", "--smartling-url option")
+	}
+
+	// config show is used to find out why values are missing
+	if !args["init"].(bool) && !args["config"].(bool) {
 		if config.UserID == "" {
 			return config, MissingConfigValueError{
 				ConfigPath: config.path,
@@ -374,6 +531,10 @@ func loadConfig(args map[string]interface{}) (Config, error) {
 		}
 
 		if config.Secret == "" {
+			if config.secretError != nil {
+				return config, config.secretError
+			}
+
 			return config, MissingConfigValueError{
 				ConfigPath: config.path,
 				EnvVarName: "SMARTLING_SECRET",
@@ -384,6 +545,20 @@ func loadConfig(args map[string]interface{}) (Config, error) {
 		}
 	}
 
+	// secret source may fail, while secret is still available from
+	// environment or command line
+	switch {
+	case config.secretError == nil:
+	case config.Secret != "":
+		logger.Warningf(
+			"secret from %s is not available, using %s instead",
+			config.getSecretSource(),
+			config.Origin("secret"),
+		)
+	case args["init"].(bool):
+		logger.Warning(config.secretError)
+	}
+
 	logger.HideFromConfig(config)
 
 	switch {
@@ -414,14 +589,30 @@ func loadConfig(args map[string]interface{}) (Config, error) {
 		}
 	}
 
+	// NOTE: threads value from config file takes precedence over option
 	if config.Threads == 0 {
 		config.Threads = int(threads)
+		config.setOrigin("threads", "--threads option or its default")
+	}
+
+	if args["--retries"] != nil {
+		retries, err := strconv.Atoi(args["--retries"].(string))
+		if err != nil || retries < 0 {
+			return config, InvalidConfigValueError{
+				ValueName:   "retries",
+				Description: "should be non-negative integer number",
+			}
+		}
+
+		config.Retry.Attempts = &retries
+		config.setOrigin("retry.attempts", "--retries option")
 	}
 
 	return config, nil
 }
 
 func createClient(
+	ctx context.Context,
 	config Config,
 	args map[string]interface{},
 ) (*smartling.Client, error) {
@@ -456,11 +647,22 @@ func createClient(
 		transport.Proxy = http.ProxyURL(proxy)
 	}
 
+	if config.SmartlingURL != "" {
+		client.BaseURL = config.SmartlingURL
+	}
+
 	if args["--smartling-url"] != nil {
 		client.BaseURL = args["--smartling-url"].(string)
 	}
 
-	client.HTTP.Transport = &transport
+	// requests in progress are aborted when context is done
+	client.HTTP.Transport = &AbortTransport{
+		Transport: newRetryTransport(
+			config.Retry,
+			newRateLimitTransport(config, &transport),
+		),
+		Context: ctx,
+	}
 	client.UserAgent = "smartling-cli/" + version
 
 	setLogger(client, logger, args["--verbose"].(int))
@@ -480,8 +682,16 @@ func createClient(
 	return client, nil
 }
 
-func doProjects(config Config, args map[string]interface{}) error {
-	client, err := createClient(config, args)
+func doProjects(
+	ctx context.Context,
+	config Config,
+	args map[string]interface{},
+) error {
+	// requests in progress are given some time to finish after interrupt
+	abort, stop := withGracePeriod(ctx, abortTimeout)
+	defer stop()
+
+	client, err := createClient(abort, config, args)
 	if err != nil {
 		return err
 	}
@@ -507,6 +717,17 @@ func doProjects(config Config, args map[string]interface{}) error {
 		return doProjectsInfo(client, config, args)
 
 	case args["locales"].(bool):
+		switch {
+		case args["add"].(bool):
+			return doProjectsLocalesAdd(client, config, args)
+
+		case args["remove"].(bool):
+			return doProjectsLocalesRemove(client, config, args)
+
+		case args["sync"].(bool):
+			return doProjectsLocalesSync(client, config, args)
+		}
+
 		return doProjectsLocales(client, config, args)
 
 	}
@@ -514,8 +735,16 @@ func doProjects(config Config, args map[string]interface{}) error {
 	return nil
 }
 
-func doFiles(config Config, args map[string]interface{}) error {
-	client, err := createClient(config, args)
+func doFiles(
+	ctx context.Context,
+	config Config,
+	args map[string]interface{},
+) error {
+	// requests in progress are given some time to finish after interrupt
+	abort, stop := withGracePeriod(ctx, abortTimeout)
+	defer stop()
+
+	client, err := createClient(abort, config, args)
 	if err != nil {
 		return err
 	}
@@ -525,16 +754,16 @@ func doFiles(config Config, args map[string]interface{}) error {
 		return doFilesList(client, config, args)
 
 	case args["pull"].(bool), args["get"].(bool):
-		return doFilesPull(client, config, args)
+		return doFilesPull(ctx, client, config, args)
 
 	case args["push"].(bool):
-		return doFilesPush(client, config, args)
+		return doFilesPush(ctx, client, config, args)
 
 	case args["status"].(bool):
-		return doFilesStatus(client, config, args)
+		return doFilesStatus(ctx, client, config, args)
 
 	case args["delete"].(bool):
-		return doFilesDelete(client, config, args)
+		return doFilesDelete(ctx, client, config, args)
 
 	case args["rename"].(bool):
 		return doFilesRename(client, config, args)
@@ -543,7 +772,7 @@ func doFiles(config Config, args map[string]interface{}) error {
 		return doFilesImport(client, config, args)
 
 	case args["upload-translation"].(bool):
-		return doFilesTranslationUpdate(client, config, args)
+		return doFilesTranslationUpdate(ctx, client, config, args)
 	}
 
 	return nil
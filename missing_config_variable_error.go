@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type MissingConfigVariableError struct {
+	ConfigPath string
+	KeyName    string
+	VarName    string
+
+	// Line and SourceLine point to value, which references variable.
+	Line       int
+	SourceLine string
+}
+
+func (err MissingConfigVariableError) Error() string {
+	return NewError(
+		fmt.Errorf(
+			"Cannot expand configuration parameter %q: "+
+				"environment variable $%s is not set",
+			err.KeyName,
+			err.VarName,
+		),
+
+		"Please, either:\n"+
+			"- Set environment variable $%s;\n"+
+			"- Or specify default value for %q option in the configuration file:\n\n\t%s\n\t\t%s",
+		err.VarName,
+		err.KeyName,
+		err.getLocation(),
+		err.getExample(),
+	).Error()
+}
+
+func (err MissingConfigVariableError) getLocation() string {
+	if err.Line == 0 {
+		return err.ConfigPath
+	}
+
+	return fmt.Sprintf("%s:%d", err.ConfigPath, err.Line)
+}
+
+// getExample returns source line with default value added to variable
+// reference, so it can be pasted into config file as is.
+func (err MissingConfigVariableError) getExample() string {
+	var (
+		reference = fmt.Sprintf("${%s}", err.VarName)
+		fallback  = fmt.Sprintf("${%s:-DEFAULT_VALUE}", err.VarName)
+	)
+
+	if !strings.Contains(err.SourceLine, reference) {
+		return fallback
+	}
+
+	return strings.TrimSpace(
+		strings.Replace(err.SourceLine, reference, fallback, 1),
+	)
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/reconquest/hierr-go"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	outputModeTable = "table"
+	outputModeJSON  = "json"
+	outputModeYAML  = "yaml"
+	outputModeCSV   = "csv"
+)
+
+// Output collects structured records produced by command and writes them
+// to stdout in machine-readable format specified by --output option.
+//
+// In default table mode commands write their human-readable output directly
+// and Output is not used.
+type Output struct {
+	sync.Mutex
+
+	Mode string
+
+	records []interface{}
+	writer  io.Writer
+}
+
+func NewOutput(args map[string]interface{}) (*Output, error) {
+	mode, _ := args["--output"].(string)
+	if mode == "" {
+		mode = outputModeTable
+	}
+
+	switch mode {
+	case outputModeTable, outputModeJSON, outputModeYAML, outputModeCSV:
+	default:
+		return nil, InvalidConfigValueError{
+			ValueName:   "output",
+			Description: "should be one of table, json, yaml or csv",
+		}
+	}
+
+	return &Output{
+		Mode:   mode,
+		writer: os.Stdout,
+	}, nil
+}
+
+// IsStructured returns true if records should be added to output instead of
+// writing human-readable text.
+func (output *Output) IsStructured() bool {
+	return output.Mode != outputModeTable
+}
+
+func (output *Output) Add(record interface{}) {
+	output.Lock()
+	defer output.Unlock()
+
+	output.records = append(output.records, record)
+}
+
+// Flush writes all collected records. It does nothing in table mode.
+func (output *Output) Flush() error {
+	output.Lock()
+	defer output.Unlock()
+
+	var err error
+
+	switch output.Mode {
+	case outputModeJSON:
+		encoder := json.NewEncoder(output.writer)
+		encoder.SetIndent("", "  ")
+
+		records := output.records
+		if records == nil {
+			records = []interface{}{}
+		}
+
+		err = encoder.Encode(records)
+
+	case outputModeYAML:
+		if len(output.records) == 0 {
+			_, err = io.WriteString(output.writer, "[]\n")
+			break
+		}
+
+		var data []byte
+
+		data, err = yaml.Marshal(output.records)
+		if err == nil {
+			_, err = output.writer.Write(data)
+		}
+
+	case outputModeCSV:
+		err = output.writeCSV()
+	}
+
+	if err != nil {
+		return hierr.Errorf(
+			err,
+			"unable to write %s output",
+			output.Mode,
+		)
+	}
+
+	output.records = nil
+
+	return nil
+}
+
+func (output *Output) writeCSV() error {
+	if len(output.records) == 0 {
+		return nil
+	}
+
+	writer := csv.NewWriter(output.writer)
+
+	var kind reflect.Type
+
+	for _, record := range output.records {
+		value := reflect.Indirect(reflect.ValueOf(record))
+
+		// commands like push --prune emit records of different kinds, so
+		// every kind gets its own header row
+		if value.Type() != kind {
+			if kind != nil {
+				writer.Flush()
+
+				_, err := io.WriteString(output.writer, "\n")
+				if err != nil {
+					return err
+				}
+			}
+
+			kind = value.Type()
+
+			var header []string
+
+			for _, field := range getRecordFields(kind) {
+				header = append(header, field.Name)
+			}
+
+			err := writer.Write(header)
+			if err != nil {
+				return err
+			}
+		}
+
+		var row []string
+
+		for _, field := range getRecordFields(value.Type()) {
+			row = append(row, formatCSVValue(value.Field(field.Index)))
+		}
+
+		err := writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+type recordField struct {
+	Name  string
+	Index int
+}
+
+// getRecordFields returns record fields in declaration order along with names
+// taken from json tags.
+func getRecordFields(kind reflect.Type) []recordField {
+	if kind.Kind() == reflect.Ptr {
+		kind = kind.Elem()
+	}
+
+	var fields []recordField
+
+	for i := 0; i < kind.NumField(); i++ {
+		name := strings.Split(kind.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fields = append(fields, recordField{Name: name, Index: i})
+	}
+
+	return fields
+}
+
+func formatCSVValue(value reflect.Value) string {
+	switch typed := value.Interface().(type) {
+	case time.Time:
+		return formatRecordTime(typed)
+
+	case smartling.UTC:
+		return formatRecordTime(typed.Time)
+	}
+
+	switch value.Kind() {
+	case reflect.Slice:
+		var items []string
+
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, fmt.Sprint(value.Index(i).Interface()))
+		}
+
+		return strings.Join(items, ";")
+
+	case reflect.Map:
+		var items []string
+
+		for _, key := range value.MapKeys() {
+			items = append(
+				items,
+				fmt.Sprintf("%v=%v", key.Interface(), value.MapIndex(key)),
+			)
+		}
+
+		sort.Strings(items)
+
+		return strings.Join(items, ";")
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+func formatRecordTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,109 @@
+package main
+
+import "time"
+
+// Records below define stable schema of --output json, yaml and csv modes.
+// Field names are taken from json tags and should never be renamed.
+
+// ProjectRecord is emitted by projects list.
+type ProjectRecord struct {
+	ProjectID      string `json:"project_id" yaml:"project_id"`
+	ProjectName    string `json:"project_name" yaml:"project_name"`
+	SourceLocaleID string `json:"source_locale_id" yaml:"source_locale_id"`
+}
+
+// ProjectDetailsRecord is emitted by projects info.
+type ProjectDetailsRecord struct {
+	ProjectID               string `json:"project_id" yaml:"project_id"`
+	AccountUID              string `json:"account_uid" yaml:"account_uid"`
+	ProjectName             string `json:"project_name" yaml:"project_name"`
+	SourceLocaleID          string `json:"source_locale_id" yaml:"source_locale_id"`
+	SourceLocaleDescription string `json:"source_locale_description" yaml:"source_locale_description"`
+	Status                  string `json:"status" yaml:"status"`
+}
+
+// LocaleRecord is emitted by projects locales.
+type LocaleRecord struct {
+	LocaleID    string `json:"locale_id" yaml:"locale_id"`
+	Description string `json:"description" yaml:"description"`
+	Enabled     bool   `json:"enabled" yaml:"enabled"`
+	Source      bool   `json:"source" yaml:"source"`
+}
+
+// FileRecord is emitted by files list.
+type FileRecord struct {
+	FileURI         string    `json:"file_uri" yaml:"file_uri"`
+	FileType        string    `json:"file_type" yaml:"file_type"`
+	LastUploaded    time.Time `json:"last_uploaded" yaml:"last_uploaded"`
+	HasInstructions bool      `json:"has_instructions" yaml:"has_instructions"`
+}
+
+// FileStatusRecord is emitted by files status for source file and every
+// target locale of every file.
+type FileStatusRecord struct {
+	FileURI  string `json:"file_uri" yaml:"file_uri"`
+	Path     string `json:"path" yaml:"path"`
+	Locale   string `json:"locale" yaml:"locale"`
+	Source   bool   `json:"source" yaml:"source"`
+	State    string `json:"state" yaml:"state"`
+	Progress int    `json:"progress" yaml:"progress"`
+	Strings  int    `json:"strings" yaml:"strings"`
+	Words    int    `json:"words" yaml:"words"`
+}
+
+// PushRecord is emitted by files push for every matched file.
+type PushRecord struct {
+	File       string            `json:"file" yaml:"file"`
+	FileURI    string            `json:"file_uri" yaml:"file_uri"`
+	FileType   string            `json:"file_type" yaml:"file_type"`
+	Namespace  string            `json:"namespace" yaml:"namespace"`
+	Directives map[string]string `json:"directives" yaml:"directives"`
+	Authorize  []string          `json:"authorize" yaml:"authorize"`
+	Status     string            `json:"status" yaml:"status"`
+	Strings    int               `json:"strings" yaml:"strings"`
+	Words      int               `json:"words" yaml:"words"`
+	Error      string            `json:"error" yaml:"error"`
+}
+
+// PullRecord is emitted by files pull for every downloaded file.
+type PullRecord struct {
+	FileURI  string `json:"file_uri" yaml:"file_uri"`
+	Locale   string `json:"locale" yaml:"locale"`
+	Path     string `json:"path" yaml:"path"`
+	Progress int    `json:"progress" yaml:"progress"`
+	Status   string `json:"status" yaml:"status"`
+}
+
+// DeleteRecord is emitted by files delete and files push --prune.
+type DeleteRecord struct {
+	FileURI string `json:"file_uri" yaml:"file_uri"`
+	Status  string `json:"status" yaml:"status"`
+}
+
+// ImportRecord is emitted by files import and files upload-translation.
+type ImportRecord struct {
+	File     string `json:"file" yaml:"file"`
+	FileURI  string `json:"file_uri" yaml:"file_uri"`
+	Locale   string `json:"locale" yaml:"locale"`
+	FileType string `json:"file_type" yaml:"file_type"`
+	Status   string `json:"status" yaml:"status"`
+	Strings  int    `json:"strings" yaml:"strings"`
+	Words    int    `json:"words" yaml:"words"`
+}
+
+// ImportErrorRecord is written to report specified by --import-errors option
+// of files import and files upload-translation.
+type ImportErrorRecord struct {
+	File      string   `json:"file" yaml:"file"`
+	FileURI   string   `json:"file_uri" yaml:"file_uri"`
+	Locale    string   `json:"locale" yaml:"locale"`
+	ImportKey string   `json:"import_key" yaml:"import_key"`
+	Hashcode  string   `json:"hashcode" yaml:"hashcode"`
+	Messages  []string `json:"messages" yaml:"messages"`
+}
+
+// ProjectLocaleRecord is emitted by projects locales add, remove and sync.
+type ProjectLocaleRecord struct {
+	LocaleID string `json:"locale_id" yaml:"locale_id"`
+	Status   string `json:"status" yaml:"status"`
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputCSV(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	output := &Output{Mode: outputModeCSV, writer: buffer}
+	output.Add(FileRecord{
+		FileURI:      "/a.json",
+		FileType:     "json",
+		LastUploaded: time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC),
+	})
+	output.Add(PushRecord{
+		FileURI:    "/b.json",
+		Directives: map[string]string{"b": "2", "a": "1"},
+		Authorize:  []string{"de-DE", "fr-FR"},
+	})
+
+	assert.NoError(t, output.Flush())
+	assert.Equal(
+		t,
+		"file_uri,file_type,last_uploaded,has_instructions\n"+
+			"/a.json,json,2020-10-01T12:00:00Z,false\n"+
+			"\n"+
+			"file,file_uri,file_type,namespace,directives,authorize,"+
+			"status,strings,words,error\n"+
+			",/b.json,,,a=1;b=2,de-DE;fr-FR,,0,0,\n",
+		buffer.String(),
+	)
+}
+
+func TestOutputJSON(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	output := &Output{Mode: outputModeJSON, writer: buffer}
+	assert.NoError(t, output.Flush())
+	assert.Equal(t, "[]\n", buffer.String())
+
+	buffer.Reset()
+
+	output.Add(DeleteRecord{FileURI: "/a.json", Status: "deleted"})
+	assert.NoError(t, output.Flush())
+	assert.JSONEq(
+		t,
+		`[{"file_uri": "/a.json", "status": "deleted"}]`,
+		buffer.String(),
+	)
+}
+
+func TestNewOutputInvalidMode(t *testing.T) {
+	_, err := NewOutput(map[string]interface{}{"--output": "xml"})
+	assert.Error(t, err)
+}
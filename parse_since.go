@@ -0,0 +1,36 @@
+package main
+
+import (
+	"time"
+)
+
+// sinceLayouts are accepted by --since option in addition to durations.
+var sinceLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseSince parses --since option value, which is either duration, like
+// 24h, counted back from now, or date and time in local timezone.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	duration, err := time.ParseDuration(value)
+	if err == nil {
+		return now.Add(-duration), nil
+	}
+
+	for _, layout := range sinceLayouts {
+		since, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return since, nil
+		}
+	}
+
+	return time.Time{}, InvalidConfigValueError{
+		ValueName: "--since",
+		Description: "should be duration like 24h or date like 2020-05-01 " +
+			"or 2020-05-01 15:04",
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2020, 5, 2, 12, 0, 0, 0, time.Local)
+
+	since, err := parseSince("36h", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 5, 1, 0, 0, 0, 0, time.Local), since)
+
+	since, err = parseSince("2020-05-01", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 5, 1, 0, 0, 0, 0, time.Local), since)
+
+	since, err = parseSince("2020-05-01 15:04", now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 5, 1, 15, 4, 0, 0, time.Local), since)
+
+	_, err = parseSince("yesterday", now)
+	assert.Error(t, err)
+}
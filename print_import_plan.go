@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// printImportPlan writes summary of imports, which are about to start,
+// grouped by locale along with reasons why other files were skipped.
+func printImportPlan(
+	writer io.Writer,
+	items []UploadItem,
+	skipped map[string]int,
+) error {
+	var (
+		files   = map[string]bool{}
+		locales = map[string]int{}
+		names   []string
+	)
+
+	for _, item := range items {
+		files[item.SourceFile.FileURI] = true
+
+		if locales[item.Locale] == 0 {
+			names = append(names, item.Locale)
+		}
+
+		locales[item.Locale]++
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintf(
+		writer,
+		"Import plan: %d translation file(s) for %d file(s) in %d locale(s).\n",
+		len(items),
+		len(files),
+		len(names),
+	)
+
+	table := NewTableWriter(writer)
+
+	for _, name := range names {
+		fmt.Fprintf(table, "  %s\t%d\n", name, locales[name])
+	}
+
+	err := RenderTable(table)
+	if err != nil {
+		return err
+	}
+
+	var reasons []string
+
+	for reason := range skipped {
+		reasons = append(reasons, reason)
+	}
+
+	sort.Strings(reasons)
+
+	for index, reason := range reasons {
+		reasons[index] = fmt.Sprintf("%d %s", skipped[reason], reason)
+	}
+
+	if len(reasons) > 0 {
+		fmt.Fprintf(writer, "Skipped: %s.\n", strings.Join(reasons, ", "))
+	}
+
+	return nil
+}
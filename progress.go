@@ -25,6 +25,11 @@ func (progress *Progress) Increment() {
 	progress.Current++
 }
 
-func (progress Progress) Flush() {
+// Flush renders current progress; it's safe to call it concurrently with
+// Increment.
+func (progress *Progress) Flush() {
+	progress.Lock()
+	defer progress.Unlock()
+
 	progress.Renderer.Render(progress)
 }
@@ -9,7 +9,7 @@ import (
 
 type ProgressRenderer struct{}
 
-func (renderer ProgressRenderer) Render(progress Progress) error {
+func (renderer ProgressRenderer) Render(progress *Progress) error {
 	_, err := fmt.Fprintf(os.Stderr, "%s\r", progress.String())
 
 	return err
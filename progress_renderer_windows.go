@@ -43,7 +43,7 @@ type (
 
 type ProgressRenderer struct{}
 
-func (renderer ProgressRenderer) Render(progress Progress) error {
+func (renderer ProgressRenderer) Render(progress *Progress) error {
 	var info consoleScreenBufferInfo
 
 	_, _, code := syscall.Syscall(
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/reconquest/hierr-go"
+)
+
+// SDK doesn't provide API for managing project target locales, so requests
+// are made directly.
+const endpointProjectLocales = "/projects-api/v2/projects/%s/targetLocales"
+
+// getProjectLocales returns set of project target locale IDs.
+func getProjectLocales(
+	client *smartling.Client,
+	project string,
+) (map[string]bool, error) {
+	details, err := client.GetProjectDetails(project)
+	if err != nil {
+		if _, ok := err.(smartling.NotFoundError); ok {
+			return nil, ProjectNotFoundError{}
+		}
+
+		return nil, hierr.Errorf(
+			err,
+			`unable to get project "%s" details`,
+			project,
+		)
+	}
+
+	locales := map[string]bool{}
+
+	for _, locale := range details.TargetLocales {
+		locales[locale.LocaleID] = true
+	}
+
+	return locales, nil
+}
+
+// getSmartlingLocale returns Smartling locale ID for given locale, which can
+// be specified as application locale from config file as well. Project
+// commands are not bound to any file, so mappings from all files sections
+// are considered along with global one and must agree with each other.
+func getSmartlingLocale(config Config, locale string) (string, error) {
+	mapped := map[string]bool{}
+
+	if smartling, ok := config.AppLocaleToLocaleMap[locale]; ok {
+		mapped[smartling] = true
+	}
+
+	for _, file := range config.Files {
+		for _, item := range file.Locales {
+			if item.Application == locale {
+				mapped[item.Smartling] = true
+			}
+		}
+	}
+
+	switch len(mapped) {
+	case 0:
+		return locale, nil
+
+	case 1:
+		for smartling := range mapped {
+			return smartling, nil
+		}
+	}
+
+	var candidates []string
+
+	for smartling := range mapped {
+		candidates = append(candidates, smartling)
+	}
+
+	sort.Strings(candidates)
+
+	return "", NewError(
+		fmt.Errorf(
+			`application locale "%s" is mapped to different Smartling `+
+				`locales: %s`,
+			locale,
+			strings.Join(candidates, ", "),
+		),
+
+		`Locales mapping differs between files sections of config file. `+
+			`Specify Smartling locale ID instead.`,
+	)
+}
+
+func addProjectLocale(
+	client *smartling.Client,
+	project string,
+	locale string,
+) error {
+	payload, err := json.Marshal(map[string]string{
+		"localeId": locale,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.Post(
+		fmt.Sprintf(endpointProjectLocales, project),
+		payload,
+		nil,
+	)
+	if err != nil {
+		return hierr.Errorf(
+			err,
+			`unable to add locale "%s" to project "%s"`,
+			locale,
+			project,
+		)
+	}
+
+	return nil
+}
+
+// removeProjectLocale removes target locale from project. Client has no
+// generic method for DELETE requests, so its HTTP client is used directly.
+func removeProjectLocale(
+	client *smartling.Client,
+	project string,
+	locale string,
+) error {
+	err := client.Authenticate()
+	if err != nil {
+		return hierr.Errorf(err, "unable to authenticate")
+	}
+
+	endpoint := fmt.Sprintf(endpointProjectLocales, project) + "?" +
+		url.Values{"localeIds": {locale}}.Encode()
+
+	request, err := http.NewRequest(
+		http.MethodDelete,
+		client.BaseURL+endpoint,
+		nil,
+	)
+	if err != nil {
+		return hierr.Errorf(err, "unable to create HTTP request")
+	}
+
+	request.Header.Set("Authorization", "Bearer "+
+		client.Credentials.AccessToken.Value)
+	request.Header.Set("User-Agent", client.UserAgent)
+
+	response, err := client.HTTP.Do(request)
+	if err != nil {
+		return hierr.Errorf(
+			err,
+			`unable to remove locale "%s" from project "%s"`,
+			locale,
+			project,
+		)
+	}
+
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return nil
+
+	case http.StatusUnauthorized:
+		return smartling.NotAuthorizedError{}
+
+	case http.StatusNotFound:
+		return ProjectNotFoundError{}
+	}
+
+	body, _ := ioutil.ReadAll(response.Body)
+
+	return hierr.Errorf(
+		smartling.APIError{
+			Cause: fmt.Errorf(
+				"API call returned unexpected HTTP code: %d",
+				response.StatusCode,
+			),
+			URL:      endpoint,
+			Response: body,
+			Headers:  &response.Header,
+		},
+		`unable to remove locale "%s" from project "%s"`,
+		locale,
+		project,
+	)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectLocalesRequests(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+
+			requests = append(requests, fmt.Sprintf(
+				"%s %s %s", request.Method, request.URL.RequestURI(), body,
+			))
+
+			writer.Header().Set("Content-Type", "application/json")
+
+			data := `{}`
+			if request.URL.Path == "/auth-api/v2/authenticate" {
+				data = `{"accessToken": "token", "expiresIn": 3600}`
+			}
+
+			fmt.Fprintf(
+				writer,
+				`{"response": {"code": "SUCCESS", "data": %s}}`,
+				data,
+			)
+		},
+	))
+	defer server.Close()
+
+	client := smartling.NewClient("user", "secret")
+	client.BaseURL = server.URL
+
+	assert.NoError(t, addProjectLocale(client, "project", "de-DE"))
+	assert.NoError(t, removeProjectLocale(client, "project", "fr-FR"))
+	assert.NoError(t, removeProjectLocale(client, "project", "b+es+419"))
+
+	assert.Equal(
+		t,
+		[]string{
+			`POST /projects-api/v2/projects/project/targetLocales {"localeId":"de-DE"}`,
+			`DELETE /projects-api/v2/projects/project/targetLocales?localeIds=fr-FR `,
+			`DELETE /projects-api/v2/projects/project/targetLocales?localeIds=b%2Bes%2B419 `,
+		},
+		requests[len(requests)-3:],
+	)
+}
+
+func TestGetSmartlingLocale(t *testing.T) {
+	config := Config{
+		Locales: []LocaleConfig{
+			{Application: "de", Smartling: "de-DE"},
+			{Application: "es", Smartling: "es-ES"},
+		},
+		Files: map[string]FileConfig{
+			"/res/**": {
+				Locales: []LocaleConfig{
+					{Application: "fr", Smartling: "fr-FR"},
+					{Application: "es", Smartling: "es-ES"},
+				},
+			},
+			"/docs/**": {
+				Locales: []LocaleConfig{
+					{Application: "es", Smartling: "es-MX"},
+				},
+			},
+		},
+	}
+
+	config.buildLocaleMaps()
+
+	locale, err := getSmartlingLocale(config, "de")
+	assert.NoError(t, err)
+	assert.Equal(t, "de-DE", locale)
+
+	locale, err = getSmartlingLocale(config, "fr")
+	assert.NoError(t, err)
+	assert.Equal(t, "fr-FR", locale)
+
+	locale, err = getSmartlingLocale(config, "ja-JP")
+	assert.NoError(t, err)
+	assert.Equal(t, "ja-JP", locale)
+
+	_, err = getSmartlingLocale(config, "es")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "es-ES, es-MX")
+}
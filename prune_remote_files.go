@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/gobwas/glob"
+	"github.com/reconquest/hierr-go"
+)
+
+// pruneScope describes which remote files are owned by push command and
+// thus can be deleted when there is no local counterpart for them.
+type pruneScope struct {
+	Branch   string
+	Patterns []glob.Glob
+	Excludes []glob.Glob
+}
+
+func newPruneScope(
+	branch string,
+	patterns []string,
+	excludes []string,
+) (pruneScope, error) {
+	scope := pruneScope{
+		Branch: branch,
+	}
+
+	for _, pattern := range patterns {
+		compiled, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return scope, NewError(
+				hierr.Errorf(err, `unable to compile pattern "%s"`, pattern),
+				`Check, that specified pattern is valid and refer to help for`+
+					` more information about glob patterns.`,
+			)
+		}
+
+		scope.Patterns = append(scope.Patterns, compiled)
+	}
+
+	for _, exclude := range excludes {
+		compiled, err := glob.Compile(exclude, '/')
+		if err != nil {
+			return scope, NewError(
+				hierr.Errorf(
+					err,
+					`unable to compile prune exclude pattern "%s"`,
+					exclude,
+				),
+				`Check, that --prune-exclude pattern is valid and refer to `+
+					`help for more information about glob patterns.`,
+			)
+		}
+
+		scope.Excludes = append(scope.Excludes, compiled)
+	}
+
+	return scope, nil
+}
+
+func (scope pruneScope) Contains(uri string) bool {
+	if !strings.HasPrefix(uri, scope.Branch) {
+		return false
+	}
+
+	name := strings.TrimPrefix(uri, scope.Branch)
+
+	for _, exclude := range scope.Excludes {
+		if exclude.Match(uri) || exclude.Match(name) {
+			return false
+		}
+	}
+
+	for _, pattern := range scope.Patterns {
+		if pattern.Match(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPruneCandidates returns remote files in given scope, which are not
+// present in the local URIs set.
+func getPruneCandidates(
+	client smartling.ClientInterface,
+	project string,
+	scope pruneScope,
+	local map[string]bool,
+) ([]smartling.File, error) {
+	files, err := listFilesRemote(client, project, scope.Branch+"**")
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []smartling.File
+
+	for _, file := range files {
+		if local[file.FileURI] || !scope.Contains(file.FileURI) {
+			continue
+		}
+
+		candidates = append(candidates, file)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].FileURI < candidates[j].FileURI
+	})
+
+	return candidates, nil
+}
+
+func pruneRemoteFiles(
+	client smartling.ClientInterface,
+	project string,
+	state *State,
+	files []smartling.File,
+	output *Output,
+	dryRun bool,
+	confirmed bool,
+) error {
+	if len(files) == 0 {
+		logger.Infof("no remote files to prune")
+
+		return nil
+	}
+
+	if dryRun {
+		reportDryRun("pruned")
+
+		for _, file := range files {
+			if output.IsStructured() {
+				output.Add(DeleteRecord{
+					FileURI: file.FileURI,
+					Status:  "planned",
+				})
+			} else {
+				fmt.Printf("%s would be pruned\n", file.FileURI)
+			}
+		}
+
+		return nil
+	}
+
+	if !confirmed {
+		for _, file := range files {
+			fmt.Fprintf(os.Stderr, "%s will be pruned\n", file.FileURI)
+		}
+
+		ok, err := confirm(
+			fmt.Sprintf("Delete %d remote files listed above?", len(files)),
+		)
+		if err != nil {
+			return NewError(
+				err,
+				`Use --yes option to prune files without confirmation.`,
+			)
+		}
+
+		if !ok {
+			logger.Infof("pruning is cancelled by user")
+
+			return nil
+		}
+	}
+
+	for _, file := range files {
+		err := client.DeleteFile(project, file.FileURI)
+		if err != nil {
+			return hierr.Errorf(
+				err,
+				`unable to delete file "%s"`,
+				file.FileURI,
+			)
+		}
+
+		state.Forget(project, file.FileURI)
+
+		if output.IsStructured() {
+			output.Add(DeleteRecord{
+				FileURI: file.FileURI,
+				Status:  "pruned",
+			})
+		} else {
+			fmt.Printf("%s pruned\n", file.FileURI)
+		}
+	}
+
+	return state.Save()
+}
+
+// getPruneScopePattern converts local file pattern into pattern in file URI
+// namespace, which is relative to directory with config file.
+func getPruneScopePattern(
+	base string,
+	directory string,
+	pattern string,
+) (string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(directory, pattern)
+	}
+
+	pattern, err := filepath.Abs(pattern)
+	if err != nil {
+		return "", hierr.Errorf(
+			err,
+			`unable to resolve absolute path to pattern: %q`,
+			pattern,
+		)
+	}
+
+	pattern, err = filepath.Rel(base, pattern)
+	if err != nil {
+		return "", hierr.Errorf(
+			err,
+			`unable to resolve relative path to pattern: %q`,
+			pattern,
+		)
+	}
+
+	return filepath.ToSlash(pattern), nil
+}
@@ -2,13 +2,18 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"./mocks"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/cuyl/smartling-cli/mocks"
 
 	smartling "github.com/Smartling/api-sdk-go"
 	"github.com/stretchr/testify/assert"
@@ -44,7 +49,7 @@ func TestPushStopUnauthorized(t *testing.T) {
 
 	client := getClient(httpClient)
 
-	err := doFilesPush(&client, getConfig(), args)
+	err := doFilesPush(context.Background(), &client, getConfig(), args)
 
 	assert.True(t, errors.Is(err, smartling.NotAuthorizedError{}))
 }
@@ -62,7 +67,7 @@ func TestPushContinueFakeError(t *testing.T) {
 		Return(nil, smartling.APIError{Cause: errors.New("some error")}).
 		Times(2)
 
-	err := doFilesPush(client, getConfig(), args)
+	err := doFilesPush(context.Background(), client, getConfig(), args)
 	assert.EqualError(
 		t,
 		err,
@@ -87,7 +92,7 @@ func TestPushStopApiError(t *testing.T) {
 		Return(nil, expectedError).
 		Once()
 
-	err := doFilesPush(client, getConfig(), args)
+	err := doFilesPush(context.Background(), client, getConfig(), args)
 
 	assert.True(t, errors.Is(err, expectedError))
 	client.AssertExpectations(t)
@@ -156,3 +161,301 @@ func mockGlobber(args map[string]interface{}) {
 		return strings.Split(fmt.Sprintf("%s", args["<file>"]), " "), nil
 	}
 }
+
+func TestPushSkipUnchanged(t *testing.T) {
+	directory, err := ioutil.TempDir("", "smartling-cli")
+	assert.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	file := filepath.Join(directory, "test.md")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("# test"), 0644))
+
+	args := getArgs(file)
+
+	mockGlobber(args)
+	defer func() {
+		globFilesLocally = globFilesLocallyFunc
+	}()
+
+	config := getConfig()
+	config.path = filepath.Join(directory, defaultConfigName)
+
+	client := &mocks.ClientInterface{}
+	client.On("UploadFile", "test", mock.Anything).
+		Return(&smartling.FileUploadResult{}, nil).
+		Once()
+
+	assert.NoError(t, doFilesPush(context.Background(), client, config, args))
+	assert.NoError(t, doFilesPush(context.Background(), client, config, args))
+
+	args["--force"] = true
+	client.On("UploadFile", "test", mock.Anything).
+		Return(&smartling.FileUploadResult{}, nil).
+		Once()
+
+	assert.NoError(t, doFilesPush(context.Background(), client, config, args))
+	client.AssertExpectations(t)
+}
+
+func TestPushPrune(t *testing.T) {
+	directory, err := ioutil.TempDir("", "smartling-cli")
+	assert.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	file := filepath.Join(directory, "a.md")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("# a"), 0644))
+
+	args := getArgs(filepath.Join(directory, "*.md"))
+	args["--prune"] = true
+	args["--prune-exclude"] = []string{"shared.md"}
+	args["--yes"] = true
+
+	globFilesLocally = func(string, string, string) ([]string, error) {
+		return []string{file}, nil
+	}
+	defer func() {
+		globFilesLocally = globFilesLocallyFunc
+	}()
+
+	config := getConfig()
+	config.path = filepath.Join(directory, defaultConfigName)
+
+	client := &mocks.ClientInterface{}
+	client.On("UploadFile", "test", mock.Anything).
+		Return(&smartling.FileUploadResult{}, nil).
+		Once()
+	client.On("ListAllFiles", "test", mock.Anything).
+		Return([]smartling.File{
+			{FileURI: "a.md"},
+			{FileURI: "b.md"},
+			{FileURI: "shared.md"},
+			{FileURI: "nested/c.md"},
+		}, nil).
+		Once()
+	client.On("DeleteFile", "test", "b.md").
+		Return(nil).
+		Once()
+
+	assert.NoError(t, doFilesPush(context.Background(), client, config, args))
+	client.AssertExpectations(t)
+}
+
+func TestPushPruneLeadingSlashPattern(t *testing.T) {
+	directory, err := ioutil.TempDir("", "smartling-cli")
+	assert.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	assert.NoError(t, os.Mkdir(filepath.Join(directory, "res"), 0755))
+	assert.NoError(t, ioutil.WriteFile(
+		filepath.Join(directory, "res", "a.md"),
+		[]byte("# a"),
+		0644,
+	))
+
+	args := getArgs("")
+	args["--prune"] = true
+	args["--yes"] = true
+
+	// patterns starting with / are relative to directory with config file,
+	// same as file URIs
+	config := getConfig()
+	config.path = filepath.Join(directory, defaultConfigName)
+	config.Files["/res/*.md"] = FileConfig{
+		Push: struct {
+			Type       string            `yaml:"type,omitempty"`
+			Directives map[string]string `yaml:"directives,omitempty,flow"`
+		}{Type: "markdown"},
+	}
+
+	client := &mocks.ClientInterface{}
+	client.On(
+		"UploadFile",
+		"test",
+		mock.MatchedBy(func(request smartling.FileUploadRequest) bool {
+			return request.FileURI == "res/a.md" &&
+				request.FileType == "markdown"
+		}),
+	).
+		Return(&smartling.FileUploadResult{}, nil).
+		Once()
+	client.On("ListAllFiles", "test", mock.Anything).
+		Return([]smartling.File{
+			{FileURI: "res/a.md"},
+			{FileURI: "res/b.md"},
+			{FileURI: "other/c.md"},
+		}, nil).
+		Once()
+	client.On("DeleteFile", "test", "res/b.md").
+		Return(nil).
+		Once()
+
+	assert.NoError(t, doFilesPush(context.Background(), client, config, args))
+	client.AssertExpectations(t)
+}
+
+// setupConcurrentPush creates files with given names in temporary project
+// directory and returns args and config to push them concurrently.
+func setupConcurrentPush(
+	t *testing.T,
+	names ...string,
+) (map[string]interface{}, Config) {
+	directory := t.TempDir()
+
+	var files []string
+
+	for _, name := range names {
+		file := filepath.Join(directory, name)
+		assert.NoError(t, ioutil.WriteFile(file, []byte("# "+name), 0644))
+
+		files = append(files, file)
+	}
+
+	args := getArgs(strings.Join(files, " "))
+	args["--output"] = "json"
+
+	globFilesLocally = func(string, string, string) ([]string, error) {
+		return files, nil
+	}
+
+	config := getConfig()
+	config.path = filepath.Join(directory, defaultConfigName)
+	config.Threads = len(names)
+
+	return args, config
+}
+
+func matchFileURI(uri string) interface{} {
+	return mock.MatchedBy(func(request smartling.FileUploadRequest) bool {
+		return request.FileURI == uri
+	})
+}
+
+func TestPushConcurrentKeepsOrder(t *testing.T) {
+	args, config := setupConcurrentPush(t, "a.md", "b.md", "c.md")
+	defer func() {
+		globFilesLocally = globFilesLocallyFunc
+	}()
+
+	// uploads finish in reverse order
+	client := &mocks.ClientInterface{}
+	client.On("UploadFile", "test", matchFileURI("a.md")).
+		After(60*time.Millisecond).
+		Return(&smartling.FileUploadResult{StringCount: 1}, nil).
+		Once()
+	client.On("UploadFile", "test", matchFileURI("b.md")).
+		After(30*time.Millisecond).
+		Return(&smartling.FileUploadResult{StringCount: 2}, nil).
+		Once()
+	client.On("UploadFile", "test", matchFileURI("c.md")).
+		Return(&smartling.FileUploadResult{StringCount: 3}, nil).
+		Once()
+
+	output := &Output{Mode: outputModeJSON, writer: ioutil.Discard}
+
+	err := pushFiles(context.Background(), client, config, args, output)
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+
+	var uris []string
+
+	for _, record := range output.records {
+		record := record.(PushRecord)
+
+		uris = append(uris, record.FileURI)
+
+		assert.Equal(t, "new", record.Status)
+		assert.Equal(t, len(uris), record.Strings)
+	}
+
+	assert.Equal(t, []string{"a.md", "b.md", "c.md"}, uris)
+}
+
+func TestPushConcurrentCollectsFailures(t *testing.T) {
+	args, config := setupConcurrentPush(t, "a.md", "b.md", "c.md", "d.md")
+	defer func() {
+		globFilesLocally = globFilesLocallyFunc
+	}()
+
+	failure := smartling.APIError{
+		Cause:   errors.New("some error"),
+		Headers: &http.Header{},
+	}
+
+	client := &mocks.ClientInterface{}
+	client.On("UploadFile", "test", matchFileURI("a.md")).
+		After(30*time.Millisecond).
+		Return(nil, failure).
+		Once()
+	client.On("UploadFile", "test", matchFileURI("b.md")).
+		Return(&smartling.FileUploadResult{}, nil).
+		Once()
+	client.On("UploadFile", "test", matchFileURI("c.md")).
+		Return(nil, failure).
+		Once()
+	client.On("UploadFile", "test", matchFileURI("d.md")).
+		After(10*time.Millisecond).
+		Return(nil, failure).
+		Once()
+
+	output := &Output{Mode: outputModeJSON, writer: ioutil.Discard}
+
+	err := pushFiles(context.Background(), client, config, args, output)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to upload 3 files")
+	client.AssertExpectations(t)
+
+	var statuses []string
+
+	for _, record := range output.records {
+		statuses = append(statuses, record.(PushRecord).Status)
+	}
+
+	assert.Equal(t, []string{"failed", "new", "failed", "failed"}, statuses)
+
+	// failed files are listed in the order they were matched, regardless
+	// of order uploads were finished
+	dir := filepath.Dir(config.path)
+	assert.Contains(
+		t,
+		err.Error(),
+		strings.Join([]string{
+			filepath.Join(dir, "a.md"),
+			filepath.Join(dir, "c.md"),
+			filepath.Join(dir, "d.md"),
+		}, ", "),
+	)
+}
+
+func TestPushConcurrentStopsOnFatalError(t *testing.T) {
+	args, config := setupConcurrentPush(t, "a.md", "b.md", "c.md", "d.md")
+	defer func() {
+		globFilesLocally = globFilesLocallyFunc
+	}()
+
+	config.Threads = 2
+
+	fatal := smartling.APIError{
+		Cause:   errors.New("maintenance"),
+		Code:    "MAINTENANCE_MODE_ERROR",
+		Headers: &http.Header{},
+	}
+
+	// a.md fails while b.md is in progress, so c.md and d.md, which are
+	// waiting for free thread, should never be uploaded; mock fails test on
+	// unexpected calls
+	client := &mocks.ClientInterface{}
+	client.On("UploadFile", "test", matchFileURI("a.md")).
+		After(30*time.Millisecond).
+		Return(nil, fatal).
+		Once()
+	client.On("UploadFile", "test", matchFileURI("b.md")).
+		After(60*time.Millisecond).
+		Return(&smartling.FileUploadResult{}, nil).
+		Once()
+
+	output := &Output{Mode: outputModeJSON, writer: ioutil.Discard}
+
+	err := pushFiles(context.Background(), client, config, args, output)
+	assert.True(t, errors.Is(err, fatal))
+	client.AssertExpectations(t)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sync"
+
+	rate "golang.org/x/time/rate"
+)
+
+const (
+	endpointClassDefault   = "default"
+	endpointClassUploads   = "uploads"
+	endpointClassDownloads = "downloads"
+	endpointClassStatus    = "status"
+	endpointClassImports   = "imports"
+)
+
+// defaultRateLimits are used for endpoint classes which are not configured
+// in rate_limit section; rate is specified in requests per second.
+var defaultRateLimits = map[string]RateLimit{
+	endpointClassDefault:   {Rate: 10, Burst: 10},
+	endpointClassUploads:   {Rate: 5, Burst: 5},
+	endpointClassDownloads: {Rate: 10, Burst: 10},
+	endpointClassStatus:    {Rate: 10, Burst: 10},
+	endpointClassImports:   {Rate: 1 / 1.5, Burst: 1},
+}
+
+// concurrencyRestoreAfter is number of successful requests after which
+// concurrency, decreased because of 429 response, is increased by one.
+const concurrencyRestoreAfter = 20
+
+var (
+	endpointImport     = regexp.MustCompile(`/locales/[^/]+/file/import$`)
+	endpointStatus     = regexp.MustCompile(`/file/(status|last-modified)$`)
+	endpointFile       = regexp.MustCompile(`/files-api/v2/projects/[^/]+/file$`)
+	endpointTranslated = regexp.MustCompile(`/locales/[^/]+/file(/zip)?$`)
+)
+
+// RateLimitTransport throttles requests to API using separate limiters for
+// different classes of endpoints and limits number of concurrent requests.
+// Concurrency is cut in half each time API replies with 429.
+type RateLimitTransport struct {
+	Transport http.RoundTripper
+
+	Limiters    map[string]*rate.Limiter
+	Concurrency *adaptiveSemaphore
+}
+
+func newRateLimitTransport(
+	config Config,
+	transport http.RoundTripper,
+) *RateLimitTransport {
+	limiters := map[string]*rate.Limiter{}
+
+	for _, class := range config.RateLimit.getClasses() {
+		limit := class.RateLimit
+		if limit.Rate == 0 {
+			limit = defaultRateLimits[class.Class]
+		}
+
+		// negative rate disables limiting for endpoint class
+		if limit.Rate < 0 {
+			continue
+		}
+
+		if limit.Burst <= 0 {
+			limit.Burst = 1
+		}
+
+		limiters[class.Class] = rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+	}
+
+	return &RateLimitTransport{
+		Transport:   transport,
+		Limiters:    limiters,
+		Concurrency: newAdaptiveSemaphore(config.Threads),
+	}
+}
+
+func (transport *RateLimitTransport) RoundTrip(
+	request *http.Request,
+) (*http.Response, error) {
+	ctx := request.Context()
+
+	if limiter, ok := transport.Limiters[getEndpointClass(request)]; ok {
+		err := limiter.Wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := transport.Concurrency.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := transport.Transport.RoundTrip(request)
+
+	transport.Concurrency.Release(
+		response != nil && response.StatusCode == http.StatusTooManyRequests,
+	)
+
+	return response, err
+}
+
+func getEndpointClass(request *http.Request) string {
+	path := request.URL.Path
+
+	switch {
+	case endpointImport.MatchString(path):
+		return endpointClassImports
+
+	case endpointStatus.MatchString(path):
+		return endpointClassStatus
+
+	case endpointFile.MatchString(path) && request.Method == http.MethodPost:
+		return endpointClassUploads
+
+	case endpointFile.MatchString(path), endpointTranslated.MatchString(path):
+		return endpointClassDownloads
+	}
+
+	return endpointClassDefault
+}
+
+type adaptiveSemaphore struct {
+	sync.Mutex
+
+	limit     int
+	max       int
+	active    int
+	successes int
+
+	changed chan struct{}
+}
+
+func newAdaptiveSemaphore(max int) *adaptiveSemaphore {
+	if max < 1 {
+		max = 1
+	}
+
+	return &adaptiveSemaphore{
+		limit:   max,
+		max:     max,
+		changed: make(chan struct{}),
+	}
+}
+
+func (semaphore *adaptiveSemaphore) Acquire(ctx context.Context) error {
+	for {
+		semaphore.Lock()
+
+		if semaphore.active < semaphore.limit {
+			semaphore.active++
+			semaphore.Unlock()
+
+			return nil
+		}
+
+		changed := semaphore.changed
+
+		semaphore.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (semaphore *adaptiveSemaphore) Release(throttled bool) {
+	semaphore.Lock()
+	defer semaphore.Unlock()
+
+	semaphore.active--
+
+	if throttled {
+		semaphore.successes = 0
+
+		if semaphore.limit > 1 {
+			semaphore.limit /= 2
+
+			logger.Warningf(
+				"API rate limit exceeded, reducing concurrency to %d",
+				semaphore.limit,
+			)
+		}
+	} else {
+		semaphore.successes++
+
+		if semaphore.successes >= concurrencyRestoreAfter &&
+			semaphore.limit < semaphore.max {
+			semaphore.successes = 0
+			semaphore.limit++
+
+			logger.Debugf("increasing concurrency to %d", semaphore.limit)
+		}
+	}
+
+	close(semaphore.changed)
+	semaphore.changed = make(chan struct{})
+}
+
+func (semaphore *adaptiveSemaphore) Limit() int {
+	semaphore.Lock()
+	defer semaphore.Unlock()
+
+	return semaphore.limit
+}
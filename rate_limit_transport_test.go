@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEndpointClass(t *testing.T) {
+	for path, classes := range map[string][2]string{
+		"/files-api/v2/projects/1/file":                    {endpointClassDownloads, endpointClassUploads},
+		"/files-api/v2/projects/1/locales/de/file":         {endpointClassDownloads, endpointClassDownloads},
+		"/files-api/v2/projects/1/locales/de/file/import":  {endpointClassImports, endpointClassImports},
+		"/files-api/v2/projects/1/file/status":             {endpointClassStatus, endpointClassStatus},
+		"/files-api/v2/projects/1/file/last-modified":      {endpointClassStatus, endpointClassStatus},
+		"/files-api/v2/projects/1/files/list":              {endpointClassDefault, endpointClassDefault},
+		"/files-api/v2/projects/1/locales/de/file/unknown": {endpointClassDefault, endpointClassDefault},
+	} {
+		for index, method := range []string{http.MethodGet, http.MethodPost} {
+			request, _ := http.NewRequest(method, "https://api"+path, nil)
+
+			assert.Equal(t, classes[index], getEndpointClass(request), path)
+		}
+	}
+}
+
+func TestAdaptiveSemaphore(t *testing.T) {
+	semaphore := newAdaptiveSemaphore(4)
+
+	assert.NoError(t, semaphore.Acquire(context.Background()))
+	semaphore.Release(true)
+	assert.Equal(t, 2, semaphore.Limit())
+
+	for i := 0; i < concurrencyRestoreAfter; i++ {
+		assert.NoError(t, semaphore.Acquire(context.Background()))
+		semaphore.Release(false)
+	}
+	assert.Equal(t, 3, semaphore.Limit())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, semaphore.Acquire(context.Background()))
+	}
+	assert.Error(t, semaphore.Acquire(ctx))
+}
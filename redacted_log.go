@@ -50,8 +50,13 @@ func (log *redactedLog) HideFromConfig(config Config) {
 	log.HideString(config.UserID)
 	log.HideString(config.AccountID)
 	log.HideString(config.ProjectID)
+	log.HideRegexp(urlCredentialsPattern)
 }
 
+// urlCredentialsPattern matches user info of URL, like user:password of
+// proxy URL.
+var urlCredentialsPattern = regexp.MustCompile(`://([^/@\s]+)@`)
+
 func (log *redactedLog) GetWriter() io.Writer {
 	return log.writer
 }
@@ -61,13 +66,20 @@ type redactedWriter struct {
 	enabled  bool
 }
 
+func (log *redactedLog) Redact(value string) string {
+	return log.writer.Redact(value)
+}
+
 func (writer redactedWriter) Write(buffer []byte) (int, error) {
+	return os.Stderr.Write([]byte(writer.Redact(string(buffer))))
+}
+
+// Redact hides sensitive values, which match any of registered patterns.
+func (writer redactedWriter) Redact(output string) string {
 	if !writer.enabled {
-		return os.Stderr.Write(buffer)
+		return output
 	}
 
-	output := string(buffer)
-
 	placeholder := "***"
 
 	for _, pattern := range writer.patterns {
@@ -90,5 +102,5 @@ func (writer redactedWriter) Write(buffer []byte) (int, error) {
 		)
 	}
 
-	return os.Stderr.Write([]byte(output))
+	return output
 }
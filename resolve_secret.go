@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/reconquest/hierr-go"
+)
+
+// resolveSecret reads token secret from external source specified in config
+// by one of secret_env, secret_file or secret_command keys. Secret set
+// explicitly takes precedence over external sources.
+func resolveSecret(config *Config) error {
+	if config.Secret != "" {
+		return nil
+	}
+
+	switch {
+	case config.SecretEnv != "":
+		config.Secret = strings.TrimSpace(os.Getenv(config.SecretEnv))
+		if config.Secret == "" {
+			return NewError(
+				fmt.Errorf(
+					"environment variable $%s specified as secret_env is empty",
+					config.SecretEnv,
+				),
+
+				`Set environment variable $%s to Smartling API token secret.`,
+				config.SecretEnv,
+			)
+		}
+
+		config.setOrigin("secret", "$"+config.SecretEnv)
+
+	case config.SecretFile != "":
+		path := config.SecretFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(config.path), path)
+		}
+
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return NewError(
+				hierr.Errorf(err, `unable to read secret file "%s"`, path),
+				`Check that file specified as secret_file exists and `+
+					`readable by current user.`,
+			)
+		}
+
+		config.Secret = strings.TrimSpace(string(contents))
+		config.setOrigin("secret", "secret_file "+path)
+
+	case config.SecretCommand != "":
+		secret, err := runSecretCommand(config.SecretCommand)
+		if err != nil {
+			return NewError(
+				hierr.Errorf(
+					err,
+					`unable to run secret command "%s"`,
+					config.SecretCommand,
+				),
+
+				`Command specified as secret_command should print Smartling `+
+					`API token secret to stdout and exit with zero code.`,
+			)
+		}
+
+		config.Secret = secret
+		config.setOrigin("secret", "secret_command")
+	}
+
+	return nil
+}
+
+// getSecretSource returns config key of external secret source, which is
+// used by resolveSecret, or empty string.
+func (config *Config) getSecretSource() string {
+	switch {
+	case config.SecretEnv != "":
+		return "secret_env"
+	case config.SecretFile != "":
+		return "secret_file"
+	case config.SecretCommand != "":
+		return "secret_command"
+	default:
+		return ""
+	}
+}
+
+// runSecretCommand runs command using system shell and returns its trimmed
+// stdout, like git credential helpers do.
+func runSecretCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	var stdout bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
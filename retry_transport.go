@@ -0,0 +1,216 @@
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryMinDelay = time.Second
+	defaultRetryMaxDelay = 30 * time.Second
+)
+
+// retryablePostPaths lists POST endpoints which can be safely repeated:
+// authentication, file upload and translation import replace previous
+// state instead of adding to it.
+var retryablePostPaths = regexp.MustCompile(
+	`/auth-api/v2/authenticate(/refresh)?$|` +
+		`/files-api/v2/projects/[^/]+/file$|` +
+		`/files-api/v2/projects/[^/]+/locales/[^/]+/file/import$`,
+)
+
+// RetryTransport repeats requests which failed with network error, 429 or
+// 5xx status using exponential backoff with jitter.
+type RetryTransport struct {
+	Transport http.RoundTripper
+
+	// Attempts is number of retries after first failed attempt.
+	Attempts int
+
+	MinDelay time.Duration
+	MaxDelay time.Duration
+}
+
+func (transport *RetryTransport) RoundTrip(
+	request *http.Request,
+) (*http.Response, error) {
+	if transport.Attempts <= 0 || !isRetryableRequest(request) {
+		return transport.Transport.RoundTrip(request)
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptRequest := request
+
+		if attempt > 0 && request.Body != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			attemptRequest = request.Clone(request.Context())
+			attemptRequest.Body = body
+		}
+
+		response, err := transport.Transport.RoundTrip(attemptRequest)
+
+		if attempt >= transport.Attempts || !isRetryableResult(response, err) {
+			return response, err
+		}
+
+		delay, ok := transport.getDelay(attempt, response)
+		if !ok {
+			logger.Warningf(
+				"not retrying %s %s: server asked to retry in %s, "+
+					"which is longer than max delay %s",
+				request.Method,
+				request.URL.Path,
+				delay,
+				transport.MaxDelay,
+			)
+
+			return response, err
+		}
+
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = response.Status
+
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		logger.Infof(
+			"retrying %s %s in %s (attempt %d of %d): %s",
+			request.Method,
+			request.URL.Path,
+			delay,
+			attempt+1,
+			transport.Attempts,
+			reason,
+		)
+
+		select {
+		case <-time.After(delay):
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		}
+	}
+}
+
+// getDelay returns delay before next attempt. Delay requested by server
+// via Retry-After is honored as is, so false is returned when it's longer
+// than max delay and request should not be retried.
+func (transport *RetryTransport) getDelay(
+	attempt int,
+	response *http.Response,
+) (time.Duration, bool) {
+	if response != nil {
+		if delay, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+			return delay, delay <= transport.MaxDelay
+		}
+	}
+
+	delay := transport.MinDelay << uint(attempt)
+	if delay <= 0 || delay > transport.MaxDelay {
+		delay = transport.MaxDelay
+	}
+
+	// half of delay is fixed and other half is random, so concurrent
+	// requests will not hit API at the same moment again
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)), true
+}
+
+func isRetryableRequest(request *http.Request) bool {
+	if request.Body != nil && request.GetBody == nil {
+		return false
+	}
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete:
+		return true
+
+	case http.MethodPost:
+		return retryablePostPaths.MatchString(request.URL.Path)
+	}
+
+	return false
+}
+
+func isRetryableResult(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter parses Retry-After header value which can be either
+// number of seconds or HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+
+		return delay, true
+	}
+
+	return 0, false
+}
+
+func newRetryTransport(
+	config RetryConfig,
+	transport http.RoundTripper,
+) *RetryTransport {
+	retry := &RetryTransport{
+		Transport: transport,
+		Attempts:  defaultRetryAttempts,
+		MinDelay:  defaultRetryMinDelay,
+		MaxDelay:  defaultRetryMaxDelay,
+	}
+
+	if config.Attempts != nil {
+		retry.Attempts = *config.Attempts
+	}
+
+	if config.MinDelay > 0 {
+		retry.MinDelay = config.MinDelay
+	}
+
+	if config.MaxDelay > 0 {
+		retry.MaxDelay = config.MaxDelay
+	}
+
+	if retry.MaxDelay < retry.MinDelay {
+		retry.MaxDelay = retry.MinDelay
+	}
+
+	return retry
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryTransportRetriesUnavailable(t *testing.T) {
+	var calls int
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			calls++
+
+			if calls < 3 {
+				writer.Header().Set("Retry-After", "0")
+				writer.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			writer.WriteHeader(http.StatusOK)
+		},
+	))
+	defer server.Close()
+
+	client := http.Client{
+		Transport: &RetryTransport{
+			Transport: http.DefaultTransport,
+			Attempts:  3,
+			MinDelay:  time.Millisecond,
+			MaxDelay:  time.Millisecond,
+		},
+	}
+
+	response, err := client.Post(
+		server.URL+"/files-api/v2/projects/123/file",
+		"text/plain",
+		strings.NewReader("contents"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+
+	response, err = client.Post(
+		server.URL+"/files-api/v2/projects/123/file/delete",
+		"text/plain",
+		strings.NewReader("contents"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryTransportHonorsRetryAfter(t *testing.T) {
+	transport := &RetryTransport{
+		MinDelay: time.Second,
+		MaxDelay: 5 * time.Second,
+	}
+
+	response := &http.Response{Header: http.Header{}}
+
+	response.Header.Set("Retry-After", "2")
+	delay, ok := transport.getDelay(0, response)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, delay)
+
+	response.Header.Set("Retry-After", "3600")
+	delay, ok = transport.getDelay(0, response)
+	assert.False(t, ok)
+	assert.Equal(t, time.Hour, delay)
+
+	var calls int
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			calls++
+
+			writer.Header().Set("Retry-After", "3600")
+			writer.WriteHeader(http.StatusTooManyRequests)
+		},
+	))
+	defer server.Close()
+
+	client := http.Client{
+		Transport: &RetryTransport{
+			Transport: http.DefaultTransport,
+			Attempts:  3,
+			MinDelay:  time.Millisecond,
+			MaxDelay:  time.Millisecond,
+		},
+	}
+
+	// response is returned as is instead of retrying earlier than server
+	// asked to
+	response, err := client.Get(server.URL + "/files-api/v2/projects/123/files/list")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, response.StatusCode)
+	assert.Equal(t, 1, calls)
+}
@@ -13,6 +13,41 @@ Special formatting functions are available:
   > {{name <variable>}} — return file URI without extension for specified
     <variable>;
   > {{ext <variable}} — return extension from file URI for specified <variable>;
+  > {{dir <variable>}}, {{base <variable>}} — return directory or last
+    element of file URI;
+  > {{lower <variable>}}, {{upper <variable>}} — change case;
+  > {{replace <old> <new> <variable>}} — replace all occurrences of <old>;
+  > {{trimPrefix <prefix> <variable>}}, {{trimSuffix <suffix> <variable>}} —
+    remove prefix or suffix, if present;
+  > {{date <layout> <variable>}} — format timestamp, like .LastUploaded,
+    using Go layout, e.g. "2006-01-02";
+  > {{language <locale>}}, {{script <locale>}}, {{region <locale>}} — return
+    language, script or region from locale ID, e.g. "sr", "Latn" and "RS"
+    for "sr-Latn-RS";
+  > {{posix <locale>}} — locale in POSIX style, e.g. "pt_BR" or
+    "sr_RS@latin";
+  > {{android <locale>}} — locale as Android resource qualifier, e.g.
+    "pt-rBR" or "b+sr+Latn+RS", to be used as "values-{{android .Locale}}";
+  > {{ios <locale>}} — locale as iOS .lproj name, e.g. "pt-BR" or "zh-Hans";
+  > {{java <locale>}} — locale as in Java resource bundle name, e.g. "pt_BR"
+    or "sr_Latn_RS".
+
+All functions can be used in pipelines: {{.FileURI | replace "/" "_"}}.
+`
+
+const formatDataHelp = `Following variables are available, same for pull, status and
+upload-translation commands:
+
+  > .FileURI — file URI in Smartling system without branch prefix;
+  > .BranchFileURI — full file URI in Smartling system with branch prefix;
+  > .Branch — branch specified by --branch option, if any;
+  > .Locale — locale ID for translated file and empty for source file;
+  > .AppLocale — application locale from locales mapping, "locales" from
+    matching files sections take precedence over global mapping;
+  > .SourceLocale — source locale ID of project;
+  > .ProjectID — project ID;
+  > .FileType — internal Smartling file type;
+  > .LastUploaded — timestamp when file was last uploaded;
 `
 
 const authenticationOptionsHelp = `
@@ -35,6 +70,110 @@ const globPatternHelp = `argument support globbing with following patterns:
   > [!xyz]  — matches not 'x', 'y' or 'z' charachers;
   > {a,b,c} — matches alternatives a, b or c;`
 
+const outputOptionHelp = `
+  --output <mode>
+    Output mode: table (default), json, yaml or csv. JSON and YAML modes
+    output list of records, CSV mode outputs header row with field names
+    followed by one row per record. Field names are stable and are listed
+    below.
+
+Output record fields:
+`
+
+const projectRecordHelp = `
+  > project_id — project ID;
+  > project_name — human-readable project name;
+  > source_locale_id — project source locale ID;
+`
+
+const projectDetailsRecordHelp = `
+  > project_id — project ID;
+  > account_uid — account ID project belongs to;
+  > project_name — human-readable project name;
+  > source_locale_id — project source locale ID;
+  > source_locale_description — human-readable source locale name;
+  > status — active or archived;
+`
+
+const localeRecordHelp = `
+  > locale_id — locale ID;
+  > description — human-readable locale description;
+  > enabled — true/false specifying is locale active or not;
+  > source — true for project source locale;
+`
+
+const fileRecordHelp = `
+  > file_uri — full file URI in Smartling system;
+  > file_type — internal Smartling file type;
+  > last_uploaded — RFC 3339 timestamp when file was last uploaded;
+  > has_instructions — true/false if file has translation instructions;
+`
+
+const fileStatusRecordHelp = `
+  > file_uri — full file URI in Smartling system;
+  > path — local file path;
+  > locale — locale ID, source locale ID for source file;
+  > source — true for source file;
+  > state — source, remote or missing (if local file does not exist);
+  > progress — translation progress in percents, -1 if file has no strings;
+  > strings — completed strings count;
+  > words — completed words count;
+`
+
+const pushRecordHelp = `
+  > file — local file path;
+  > file_uri — full file URI in Smartling system, including branch;
+  > file_type — Smartling file type;
+  > namespace — namespace directive value;
+  > directives — all directives sent with file;
+  > authorize — locales to authorize, "all" for --authorize;
+  > status — new, overwritten, unchanged, failed, interrupted or planned
+    (--dry-run);
+  > strings — strings count in uploaded file;
+  > words — words count in uploaded file;
+  > error — upload error for failed files;
+
+Files deleted by --prune are emitted as records with following fields:
+
+  > file_uri — full file URI in Smartling system;
+  > status — pruned or planned (--dry-run);
+`
+
+const pullRecordHelp = `
+  > file_uri — full file URI in Smartling system;
+  > locale — locale ID, empty for source file;
+  > path — local file path;
+  > progress — translation progress in percents;
+  > status — created, updated or unchanged;
+`
+
+const deleteRecordHelp = `
+  > file_uri — full file URI in Smartling system;
+  > status — deleted or planned (--dry-run);
+`
+
+const importErrorsOptionHelp = `
+  --import-errors <path>
+    Write translation import errors, which Smartling reports for imported
+    files, into report file: local file, file URI, locale, import key,
+    string hashcode and messages. Report is written in CSV format if path
+    ends with .csv and in JSON format otherwise.
+
+  --max-import-errors <number>
+    Exit with non-zero code if more than <number> translation import errors
+    are found. By default import errors are only reported as warnings.
+`
+
+const importRecordHelp = `
+  > file — local file path;
+  > file_uri — file URI in Smartling system;
+  > locale — locale translations were imported into;
+  > file_type — Smartling file type;
+  > status — imported or planned (--dry-run);
+  > strings — imported strings count;
+  > words — imported words count;
+`
+
 const initHelp = `smartling-cli init — create config file interactively.
 
 Walk down common config file parameters and fill them through dialog.
@@ -48,6 +187,15 @@ config values prior dialog:
 
   smartling-cli init --user=your_user_id
 
+Token secret can be stored either in config file or outside of it, so config
+can be safely committed:
+
+  > file — secret is written to separate file (secret_file key), which
+    should not be committed;
+  > command — secret is printed by specified command (secret_command key),
+    e.g. password manager CLI;
+  > env — secret is read from environment variable (secret_env key).
+
 Also, --dry-run option can be used to just look at resulting config without
 overwritting anything:
 
@@ -72,6 +220,80 @@ Default config values can be passed via following options:` +
     Specify default project.
 `
 
+const configValidateHelp = `smartling-cli config validate — check config file.
+
+Checks config file offline, without connecting to Smartling API, and lists
+found problems along with line numbers:
+
+  > unknown keys, which are otherwise silently ignored;
+  > values of wrong type;
+  > malformed file patterns in files section;
+  > pull formats, which can't be compiled;
+  > missing, duplicate or conflicting locale mappings;
+  > unknown push directives (reported as warnings);
+  > unset environment variables (reported as warnings).
+
+Command exits with non-zero code if any error is found.
+
+  smartling-cli config validate --config=/path/to/project/smartling.yml
+
+
+Available options:
+  -c --config <file>
+    Specify config file to validate.
+`
+
+const configShowHelp = `smartling-cli config show — show effective config.
+
+Displays config values, which will be used by other commands, along with
+their origin: config file, profile, environment variable, command line
+option or default.
+
+Values can be set in several places and the following precedence is used,
+from lowest to highest:
+
+  > default value;
+  > config file;
+  > profile selected by --profile or SMARTLING_PROFILE;
+  > secret_file, secret_command or secret_env for token secret;
+  > SMARTLING_USER_ID, SMARTLING_SECRET and SMARTLING_PROJECT_ID environment
+    variables, only if value is not set in config;
+  > command line options.
+
+Note, that "threads" value from config file takes precedence over --threads
+option.
+
+If token secret can't be read from secret_env or secret_file, the error is
+displayed after values. Command specified by secret_command is not run.
+
+Sensitive values, like credentials, IDs and proxy password, are always
+partially hidden.
+
+If <uri> is specified, values from files section, which will be used for
+given file URI or path, are displayed as well:
+
+  smartling-cli config show /path/to/file.properties
+
+All sections of files config, which match given file, are merged from least
+to most specific one, so values from more specific sections take precedence:
+
+  > default section is the least specific and applies to all files;
+  > pattern with more literal (non-wildcard) characters is more specific;
+  > if equal, pattern with less ** wildcards is more specific;
+  > if equal, pattern with less other wildcards is more specific;
+  > otherwise patterns are ordered alphabetically.
+
+Push directives are merged key by key. Applied sections are logged with -vv.
+
+
+Available options:
+  -c --config <file>
+    Specify config file to use.
+
+  --profile <name>
+    Specify profile to use.
+`
+
 const projectsListHelp = `smartling-cli projects list — list projects from account.
 
 Command will list projects from specified account in tabular format with
@@ -90,7 +312,8 @@ option to be able to see projects list.
 Available options:
   -s --short
     List only project IDs.
-` + authenticationOptionsHelp
+` + authenticationOptionsHelp +
+	outputOptionHelp + projectRecordHelp
 
 const projectsInfoHelp = `smartling-cli projects info — show detailed project info.
 
@@ -99,7 +322,8 @@ Displays detailed information for specific project.
 Project should be specified either in config or via --project option.
 
 
-Available options:` + authenticationOptionsHelp
+Available options:` + authenticationOptionsHelp +
+	outputOptionHelp + projectDetailsRecordHelp
 
 const projectsLocalesHelp = `smartling-cli projects locales — list target locales.
 
@@ -123,7 +347,70 @@ Available options:
 
   --format
     Use specific output format instead of default.
-` + authenticationOptionsHelp
+` + authenticationOptionsHelp +
+	outputOptionHelp + localeRecordHelp
+
+const projectLocaleRecordHelp = `
+  > locale_id — Smartling locale ID;
+  > status — added, removed, planned (--dry-run), exists, not found or
+    not in config;
+`
+
+const projectsLocalesAddHelp = `smartling-cli projects locales add — add target locales.
+
+Adds specified target locales to project. Locales can be specified either as
+Smartling locale IDs or as application locales from config file, including
+locales mappings of files sections. Locales, which are already added, are
+skipped.
+
+  smartling-cli projects locales add de-DE fr-FR
+
+
+Available options:
+  -p --project <project>
+    Specify project to use.
+
+  --dry-run
+    Do not add anything, only output list of locales to add.
+` + authenticationOptionsHelp +
+	outputOptionHelp + projectLocaleRecordHelp
+
+const projectsLocalesRemoveHelp = `smartling-cli projects locales remove — remove target locales.
+
+Removes specified target locales from project. Locales can be specified
+either as Smartling locale IDs or as application locales from config file,
+including locales mappings of files sections.
+
+  smartling-cli projects locales remove de-DE
+
+
+Available options:
+  -p --project <project>
+    Specify project to use.
+
+  --dry-run
+    Do not remove anything, only output list of locales to remove.
+` + authenticationOptionsHelp +
+	outputOptionHelp + projectLocaleRecordHelp
+
+const projectsLocalesSyncHelp = `smartling-cli projects locales sync — add locales from config.
+
+Compares "locales" section of config file with project target locales and
+adds locales, which are missing in project. Project locales, which are not
+listed in config file, are reported as "not in config", but not removed; use
+"projects locales remove" to remove them.
+
+  smartling-cli projects locales sync --dry-run
+
+
+Available options:
+  -p --project <project>
+    Specify project to use.
+
+  --dry-run
+    Do not add anything, only output list of locales to add.
+` + authenticationOptionsHelp +
+	outputOptionHelp + projectLocaleRecordHelp
 
 const filesListHelp = `smartling-cli files list — list files from project.
 
@@ -158,7 +445,8 @@ Available options:
 
   --format <format>
     Override default listing format.
-` + authenticationOptionsHelp
+` + authenticationOptionsHelp +
+	outputOptionHelp + fileRecordHelp
 
 const filesPullHelp = `smartling-cli files pull — downloads translated files from project.
 
@@ -183,15 +471,26 @@ directory name you want to download into.
 
 To download source file as well as translated files specify --source option.
 
+Downloaded files are written atomically, so interrupted download never leaves
+partially written file behind. Files, which contents match downloaded ones, are
+not touched at all. Every downloaded file is reported as created, updated or
+unchanged.
+
+Pull records last modification time of every downloaded file translation in
+the ".smartling.state" file located next to the config file, separately for
+every project, retrieval type and output path. Translations, which were not
+modified since last pull and which still exist locally, are not downloaded
+again. Use --full option to download all translations anyway.
+
+If any file or locale fails to download, pull continues with other files and
+then prints summary of failures and exits with non-zero code. Use --fail-fast
+option to stop on the first failure.
+
 Files will be downloaded and stored under names used while upload (e.g. File
 URI). While downloading translated file suffix "_<locale>" will be appended to
 file name before extension. To override file format name, use --format option.
 ` + formatOptionHelp + `
-Following variables are available:
-
-  > .FileURI — full file URI in Smartling system;
-  > .Locale — locale ID for translated file and empty for source file;
-
+` + formatDataHelp + `
 
 Available options:
   -p --project <project>
@@ -200,6 +499,13 @@ Available options:
   --source
     Download source files along with translated files.
 
+  --full
+    Download all files, ignoring modification times recorded by previous
+    pull.
+
+  --fail-fast
+    Do not start new downloads after first failure.
+
   —d ——directory <dir>
     Download files into specified directory.
 
@@ -217,9 +523,10 @@ Available options:
     > pseudo — returns modified version of original text with certain
                characters transformed;
     > contextMatchingInstrumented — to use with Chrome Context Capture;
-` + authenticationOptionsHelp
+` + authenticationOptionsHelp +
+	outputOptionHelp + pullRecordHelp
 
-const filesPushHelp = `smartling-cli files push <file> [<uri>] [--type <type>] [--branch (@auto|<branch name>)] [--authorize|--locale <locale>] [--directory <work dir>] [--directive <smartling directive>]
+const filesPushHelp = `smartling-cli files push <file> [<uri>] [--type <type>] [--branch (@auto|<branch name>)] [--authorize|--locale <locale>] [--directory <work dir>] [--directive <smartling directive>] [--force] [--prune [--prune-exclude <uri>]... [--yes]]
 
 Uploads files designated for translation.
 
@@ -242,6 +549,20 @@ File type will be deduced from file extension. If file extension is unknown,
 type should be specified manually by using --type option. That option also
 can be used to override detected file type.
 
+To delete remote files, which were pushed before, but no longer exist locally,
+use --prune option. Only files with the same branch prefix, which URIs match
+push patterns, are considered. Shared files can be protected from pruning by
+one or more --prune-exclude patterns. Prune asks for confirmation unless --yes
+option is given, and it's skipped if any upload fails.
+
+Files are uploaded concurrently using at most --threads uploads at a time.
+Results are printed in the same order files were matched.
+
+Push keeps track of uploaded files in the ".smartling.state" file located next
+to the config file. Files, which contents, type, directives and authorization
+options are not changed since last successful push into the same project, are
+skipped. Use --force option to upload them anyway.
+
 <file> ` + globPatternHelp + `
 
 
@@ -261,7 +582,24 @@ Available options:
 
   --type <type>
     Override automatically detected file type.
-` + authenticationOptionsHelp
+
+  --force
+    Upload all matched files, even if they are not changed since last push.
+
+  --prune
+    Delete remote files, which have no local counterpart.
+
+  --prune-exclude <uri>
+    Do not prune remote files matching specified pattern.
+
+  --yes
+    Prune files without confirmation.
+
+  --dry-run
+    Do not upload anything, only output list of files to upload with
+    their URIs, types, directives, namespaces and locales to authorize.
+` + authenticationOptionsHelp +
+	outputOptionHelp + pushRecordHelp
 
 const filesStatusHelp = `smartling-cli files status — show files status from project.
 
@@ -286,10 +624,13 @@ To list files status from specific directory, --directory option can be used.
 
 To override default file name format --format can be used.
 ` + formatOptionHelp + `
-Following variables are available:
+` + formatDataHelp + `
+Status command can be used as CI gate: if any of --min-progress,
+--min-file-progress or --require-locale options is specified, command will
+exit with non-zero code and print offending file/locale pairs when
+thresholds are not met.
 
-  > .FileURI — full file URI in Smartling system;
-  > .Locale — locale ID for translated file and empty for source file;
+  smartling-cli files status --min-progress=90 --min-progress=de-DE=100
 
 <uri> ` + globPatternHelp + `
 
@@ -303,7 +644,25 @@ Available options:
 
   --format <format>
     Specify format for listing file names.
-` + authenticationOptionsHelp
+
+  --min-progress <[locale=]percent>
+    Require translation progress of every file into every locale to be at
+    least specified percent. If locale is specified, threshold is applied
+    only to that locale and overrides global one. Can be repeated.
+
+  --min-file-progress <percent>
+    Require file progress across all its locales to be at least specified
+    percent.
+
+  --require-locale <locale>
+    Require every file to be translated into specified locale. Can be
+    repeated.
+
+  --junit <path>
+    Write results of threshold checks into specified file in JUnit XML
+    format, each file/locale pair as separate test case.
+` + authenticationOptionsHelp +
+	outputOptionHelp + fileStatusRecordHelp
 
 const filesDeleteHelp = `smartling-cli files delete — removes files from project.
 
@@ -319,7 +678,11 @@ to read files list from stdin:
 Available options:
   -p --project <project>
     Specify project to use.
-` + authenticationOptionsHelp
+
+  --dry-run
+    Do not delete anything, only output list of matched file URIs.
+` + authenticationOptionsHelp +
+	outputOptionHelp + deleteRecordHelp
 
 const filesRenameHelp = `smartling-cli files rename — rename specified file.
 
@@ -328,6 +691,9 @@ Renames specified file URI into new file URI.
 Available options:
   -p --project <project>
     Specify project to use.
+
+  --dry-run
+    Do not rename anything, only output what would be renamed.
 ` + authenticationOptionsHelp
 
 const importHelp = `smartling-cli import — import file translations.
@@ -352,13 +718,93 @@ Available options:
 
   --overwrite
     Overwrite existing translations.
-` + authenticationOptionsHelp
+
+  --dry-run
+    Do not import anything, only output file, URI, locale, type and
+    translation state.
+` + importErrorsOptionHelp + authenticationOptionsHelp +
+	outputOptionHelp + importRecordHelp
+
+const uploadTranslationHelp = `smartling-cli files upload-translation — import local translations.
+
+Finds local translations for remote files matching <uri> and imports them
+into Smartling. Local file paths are computed using pull format from config
+file for every project target locale, so files downloaded by pull command can
+be uploaded back after they were edited locally.
+
+Locales mapping from "locales" of matching files sections is merged over
+global mapping to compute .AppLocale, same as for pull command.
+
+` + formatDataHelp + `
+Before imports start, plan summary with number of files per locale and
+number of skipped files is written to stderr.
+
+To import only locales delivered by translation vendor, use --locale option
+one or more times, either with Smartling or application locale IDs:
+
+  smartling-cli files upload-translation --locale=de-DE --locale=fr
+
+To import only files changed recently, use --since option with date or
+duration:
+
+  smartling-cli files upload-translation --since=24h
+
+Copy-edited source-language files, which paths are computed with empty
+.Locale same as for pull --source, can be imported into specified locale
+using --source-locale option.
+
+Failed imports are summarized at the end and make command exit with non-zero
+code. Use --fail-fast option to stop on the first failure.
+
+<uri> ` + globPatternHelp + `
+
+Available options:
+  -b --branch <branch>
+    Operate only on files with specified URI prefix.
+
+  --published
+    The translated content is published.
+
+  --post-translation
+   The translated content is imported into the first step after translation
+   If there are none, it will be published.
+
+  --overwrite
+    Overwrite existing translations.
+
+  -l --locale <locale>
+    Import only specified locales. Can be specified several times.
+
+  --since <time>
+    Import only local files modified since specified time. Time can be
+    specified as duration, e.g. 24h, or as date, e.g. 2020-05-01 or
+    "2020-05-01 15:04".
+
+  --source-locale <locale>
+    Import local source-language files into specified locale.
+
+  --fail-fast
+    Do not start new imports after first failure.
+
+  --dry-run
+    Do not import anything, only output list of imports as local file,
+    URI and locale triples.
+` + importErrorsOptionHelp + authenticationOptionsHelp +
+	outputOptionHelp + importRecordHelp
 
 func showHelp(args map[string]interface{}) {
 	switch {
 	case args["init"].(bool):
 		fmt.Print(initHelp)
 
+	case args["config"].(bool):
+		switch {
+		case args["validate"].(bool):
+			fmt.Print(configValidateHelp)
+		case args["show"].(bool):
+			fmt.Print(configShowHelp)
+		}
+
 	case args["projects"].(bool):
 		switch {
 		case args["list"].(bool):
@@ -366,7 +812,16 @@ func showHelp(args map[string]interface{}) {
 		case args["info"].(bool):
 			fmt.Print(projectsInfoHelp)
 		case args["locales"].(bool):
-			fmt.Print(projectsLocalesHelp)
+			switch {
+			case args["add"].(bool):
+				fmt.Print(projectsLocalesAddHelp)
+			case args["remove"].(bool):
+				fmt.Print(projectsLocalesRemoveHelp)
+			case args["sync"].(bool):
+				fmt.Print(projectsLocalesSyncHelp)
+			default:
+				fmt.Print(projectsLocalesHelp)
+			}
 		}
 
 	case args["files"].(bool):
@@ -385,6 +840,8 @@ func showHelp(args map[string]interface{}) {
 			fmt.Print(filesRenameHelp)
 		case args["import"].(bool):
 			fmt.Print(importHelp)
+		case args["upload-translation"].(bool):
+			fmt.Print(uploadTranslationHelp)
 		}
 
 	default:
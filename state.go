@@ -0,0 +1,260 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/reconquest/hierr-go"
+)
+
+const defaultStateName = ".smartling.state"
+
+// State is stored next to config file and keeps track of what was already
+// transferred to or from Smartling, so consequent runs can skip unchanged
+// files.
+type State struct {
+	sync.Mutex `json:"-"`
+
+	// Push contains descriptions of uploaded files keyed by project ID and
+	// file URI, see getStateFileKey.
+	Push map[string]PushStateEntry `json:"push,omitempty"`
+
+	// Pull contains last modification timestamps of downloaded files keyed
+	// by project ID and file URI and then by locale, retrieval type and
+	// output path, see PullStateKey; source file has empty locale.
+	Pull map[string]map[string]time.Time `json:"pull,omitempty"`
+
+	path     string
+	modified bool
+}
+
+// PushStateEntry describes file as it was uploaded last time, including
+// upload options, which change what Smartling does with the file.
+type PushStateEntry struct {
+	Hash               string            `json:"hash"`
+	Type               string            `json:"type"`
+	Directives         map[string]string `json:"directives,omitempty"`
+	Authorize          bool              `json:"authorize,omitempty"`
+	LocalesToAuthorize []string          `json:"locales_to_authorize,omitempty"`
+}
+
+func loadState(config Config) (*State, error) {
+	state := &State{
+		Push: map[string]PushStateEntry{},
+		Pull: map[string]map[string]time.Time{},
+	}
+
+	// state can be persisted only when we know where config file is
+	if config.path == "" {
+		return state, nil
+	}
+
+	state.path = filepath.Join(filepath.Dir(config.path), defaultStateName)
+
+	contents, err := ioutil.ReadFile(state.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+
+		return nil, NewError(
+			hierr.Errorf(err, `unable to read state file "%s"`, state.path),
+			`Check that state file is readable by current user.`,
+		)
+	}
+
+	err = json.Unmarshal(contents, state)
+	if err != nil {
+		return nil, NewError(
+			hierr.Errorf(err, `unable to parse state file "%s"`, state.path),
+			`State file is corrupted. Remove it to start from scratch.`,
+		)
+	}
+
+	if state.Push == nil {
+		state.Push = map[string]PushStateEntry{}
+	}
+
+	if state.Pull == nil {
+		state.Pull = map[string]map[string]time.Time{}
+	}
+
+	return state, nil
+}
+
+// IsPushed returns true if file with given URI was already uploaded into
+// project with the same contents and upload options.
+func (state *State) IsPushed(
+	project string,
+	uri string,
+	entry PushStateEntry,
+) bool {
+	state.Lock()
+	defer state.Unlock()
+
+	previous, ok := state.Push[getStateFileKey(project, uri)]
+	if !ok {
+		return false
+	}
+
+	return reflect.DeepEqual(
+		previous.normalize(),
+		entry.normalize(),
+	)
+}
+
+func (state *State) SetPushed(project string, uri string, entry PushStateEntry) {
+	state.Lock()
+	defer state.Unlock()
+
+	state.Push[getStateFileKey(project, uri)] = entry
+	state.modified = true
+}
+
+func (state *State) Forget(project string, uri string) {
+	state.Lock()
+	defer state.Unlock()
+
+	key := getStateFileKey(project, uri)
+
+	if _, ok := state.Push[key]; ok {
+		delete(state.Push, key)
+		state.modified = true
+	}
+}
+
+// normalize returns copy of entry, which can be compared with entry loaded
+// from state file: empty collections are omitted in JSON and order of
+// locales doesn't matter.
+func (entry PushStateEntry) normalize() PushStateEntry {
+	if len(entry.Directives) == 0 {
+		entry.Directives = nil
+	}
+
+	if len(entry.LocalesToAuthorize) == 0 {
+		entry.LocalesToAuthorize = nil
+	} else {
+		locales := append([]string{}, entry.LocalesToAuthorize...)
+		sort.Strings(locales)
+
+		entry.LocalesToAuthorize = locales
+	}
+
+	return entry
+}
+
+// PullStateKey identifies downloaded file translation. Same translation
+// can be downloaded with different retrieval types or into different paths,
+// so these are part of the key too.
+type PullStateKey struct {
+	Project   string
+	FileURI   string
+	Locale    string
+	Retrieval string
+	Path      string
+}
+
+func (key PullStateKey) file() string {
+	return getStateFileKey(key.Project, key.FileURI)
+}
+
+// target returns key of downloaded translation within file entry. Path is
+// made absolute, so running pull from different directories doesn't mix up
+// entries.
+func (key PullStateKey) target() string {
+	path, err := filepath.Abs(key.Path)
+	if err != nil {
+		path = key.Path
+	}
+
+	return key.Locale + ":" + key.Retrieval + ":" + path
+}
+
+// IsPulled returns true if file translation was already downloaded and it
+// wasn't modified since then.
+func (state *State) IsPulled(key PullStateKey, modified time.Time) bool {
+	state.Lock()
+	defer state.Unlock()
+
+	pulled, ok := state.Pull[key.file()][key.target()]
+	if !ok || modified.IsZero() {
+		return false
+	}
+
+	return !modified.After(pulled)
+}
+
+func (state *State) SetPulled(key PullStateKey, modified time.Time) {
+	state.Lock()
+	defer state.Unlock()
+
+	if modified.IsZero() {
+		return
+	}
+
+	file := key.file()
+
+	if state.Pull[file] == nil {
+		state.Pull[file] = map[string]time.Time{}
+	}
+
+	state.Pull[file][key.target()] = modified
+	state.modified = true
+}
+
+func (state *State) Save() error {
+	state.Lock()
+	defer state.Unlock()
+
+	if state.path == "" || !state.modified {
+		return nil
+	}
+
+	contents, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return hierr.Errorf(err, "unable to encode state")
+	}
+
+	temp := state.path + ".tmp"
+
+	err = ioutil.WriteFile(temp, contents, 0644)
+	if err != nil {
+		return NewError(
+			hierr.Errorf(err, `unable to write state file "%s"`, temp),
+			`Check that directory with config file is writable.`,
+		)
+	}
+
+	err = os.Rename(temp, state.path)
+	if err != nil {
+		return NewError(
+			hierr.Errorf(err, `unable to replace state file "%s"`, state.path),
+			`Check that directory with config file is writable.`,
+		)
+	}
+
+	state.modified = false
+
+	return nil
+}
+
+// getStateFileKey returns key of file in state. Same URI can be used in
+// different projects, so project ID is part of the key. Branch is already
+// included in the URI as prefix.
+func getStateFileKey(project string, uri string) string {
+	return project + ":" + uri
+}
+
+func hashContents(contents []byte) string {
+	sum := sha256.Sum256(contents)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatePullRoundTrip(t *testing.T) {
+	directory, err := ioutil.TempDir("", "smartling-cli")
+	assert.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	config := Config{path: filepath.Join(directory, defaultConfigName)}
+
+	state, err := loadState(config)
+	assert.NoError(t, err)
+
+	var (
+		modified = time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+		key      = PullStateKey{
+			Project:   "project-a",
+			FileURI:   "a.json",
+			Locale:    "de-DE",
+			Retrieval: "published",
+			Path:      "de-DE/a.json",
+		}
+	)
+
+	assert.False(t, state.IsPulled(key, modified))
+
+	state.SetPulled(key, modified)
+	assert.NoError(t, state.Save())
+
+	state, err = loadState(config)
+	assert.NoError(t, err)
+
+	assert.True(t, state.IsPulled(key, modified))
+	assert.False(t, state.IsPulled(key, modified.Add(time.Second)))
+	assert.False(t, state.IsPulled(key, time.Time{}))
+
+	other := key
+	other.Locale = "fr-FR"
+	assert.False(t, state.IsPulled(other, modified))
+
+	other = key
+	other.Project = "project-b"
+	assert.False(t, state.IsPulled(other, modified))
+
+	other = key
+	other.Retrieval = "pseudo"
+	assert.False(t, state.IsPulled(other, modified))
+
+	other = key
+	other.Path = "translations/de-DE/a.json"
+	assert.False(t, state.IsPulled(other, modified))
+}
+
+func TestStatePushComparesProjectAndOptions(t *testing.T) {
+	state, err := loadState(Config{})
+	assert.NoError(t, err)
+
+	entry := PushStateEntry{
+		Hash:               "abc",
+		Type:               "json",
+		Authorize:          false,
+		LocalesToAuthorize: []string{"fr-FR", "de-DE"},
+	}
+
+	state.SetPushed("project-a", "branch/a.json", entry)
+
+	assert.True(t, state.IsPushed("project-a", "branch/a.json", entry))
+	assert.False(t, state.IsPushed("project-b", "branch/a.json", entry))
+	assert.False(t, state.IsPushed("project-a", "a.json", entry))
+
+	reordered := entry
+	reordered.LocalesToAuthorize = []string{"de-DE", "fr-FR"}
+	assert.True(t, state.IsPushed("project-a", "branch/a.json", reordered))
+
+	authorized := entry
+	authorized.Authorize = true
+	assert.False(t, state.IsPushed("project-a", "branch/a.json", authorized))
+
+	narrowed := entry
+	narrowed.LocalesToAuthorize = []string{"de-DE"}
+	assert.False(t, state.IsPushed("project-a", "branch/a.json", narrowed))
+
+	state.Forget("project-a", "branch/a.json")
+	assert.False(t, state.IsPushed("project-a", "branch/a.json", entry))
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/reconquest/hierr-go"
+)
+
+// StatusGate checks translation progress reported by files status against
+// thresholds, so status command can be used to fail CI pipelines.
+type StatusGate struct {
+	sync.Mutex
+
+	// MinProgress is applied to every file/locale pair, -1 if not set.
+	MinProgress int
+
+	// LocaleProgress overrides MinProgress for specific locales.
+	LocaleProgress map[string]int
+
+	// MinFileProgress is applied to file progress across all its locales,
+	// -1 if not set.
+	MinFileProgress int
+
+	// RequiredLocales should be present in every file.
+	RequiredLocales []string
+
+	checks []StatusCheck
+}
+
+// StatusCheck is a result of single threshold check.
+type StatusCheck struct {
+	FileURI   string
+	Locale    string
+	Rule      string
+	Progress  int
+	Threshold int
+	Message   string
+}
+
+func (check StatusCheck) Passed() bool {
+	return check.Message == ""
+}
+
+func NewStatusGate(args map[string]interface{}) (*StatusGate, error) {
+	var (
+		minProgress, _     = args["--min-progress"].([]string)
+		minFileProgress, _ = args["--min-file-progress"].(string)
+		required, _        = args["--require-locale"].([]string)
+	)
+
+	gate := &StatusGate{
+		MinProgress:     -1,
+		MinFileProgress: -1,
+		LocaleProgress:  map[string]int{},
+		RequiredLocales: required,
+	}
+
+	for _, spec := range minProgress {
+		locale := ""
+
+		if parts := strings.SplitN(spec, "=", 2); len(parts) == 2 {
+			locale, spec = parts[0], parts[1]
+		}
+
+		percents, err := parsePercents(spec)
+		if err != nil {
+			return nil, InvalidConfigValueError{
+				ValueName:   "min-progress",
+				Description: "should be either <percents> or <locale>=<percents>",
+			}
+		}
+
+		if locale == "" {
+			gate.MinProgress = percents
+		} else {
+			gate.LocaleProgress[strings.ToLower(locale)] = percents
+		}
+	}
+
+	if minFileProgress != "" {
+		percents, err := parsePercents(minFileProgress)
+		if err != nil {
+			return nil, InvalidConfigValueError{
+				ValueName:   "min-file-progress",
+				Description: "should be integer percents between 0 and 100",
+			}
+		}
+
+		gate.MinFileProgress = percents
+	}
+
+	return gate, nil
+}
+
+func parsePercents(value string) (int, error) {
+	percents, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil {
+		return 0, err
+	}
+
+	if percents < 0 || percents > 100 {
+		return 0, fmt.Errorf("percents out of range: %d", percents)
+	}
+
+	return percents, nil
+}
+
+// Enabled returns true if any threshold is specified.
+func (gate *StatusGate) Enabled() bool {
+	return gate.MinProgress >= 0 ||
+		gate.MinFileProgress >= 0 ||
+		len(gate.LocaleProgress) > 0 ||
+		len(gate.RequiredLocales) > 0
+}
+
+// Check checks specified file status against all thresholds.
+func (gate *StatusGate) Check(status *smartling.FileStatus) {
+	gate.Lock()
+	defer gate.Unlock()
+
+	var (
+		uri       = status.FileURI
+		completed int
+	)
+
+	for _, translation := range status.Items {
+		completed += translation.CompletedStringCount
+
+		threshold, ok := gate.LocaleProgress[strings.ToLower(translation.LocaleID)]
+		if !ok {
+			threshold = gate.MinProgress
+		}
+
+		if threshold < 0 {
+			continue
+		}
+
+		progress := getProgress(
+			translation.CompletedStringCount,
+			status.TotalStringCount,
+		)
+
+		check := StatusCheck{
+			FileURI:   uri,
+			Locale:    translation.LocaleID,
+			Rule:      "min-progress",
+			Progress:  progress,
+			Threshold: threshold,
+		}
+
+		if progress < threshold {
+			check.Message = fmt.Sprintf(
+				"progress %d%% is below %d%%",
+				progress,
+				threshold,
+			)
+		}
+
+		gate.checks = append(gate.checks, check)
+	}
+
+	for _, locale := range gate.RequiredLocales {
+		var locales []string
+
+		for _, translation := range status.Items {
+			locales = append(locales, translation.LocaleID)
+		}
+
+		check := StatusCheck{
+			FileURI: uri,
+			Locale:  locale,
+			Rule:    "require-locale",
+		}
+
+		if !hasLocaleInList(locale, locales) {
+			check.Message = "locale is missing"
+		}
+
+		gate.checks = append(gate.checks, check)
+	}
+
+	if gate.MinFileProgress >= 0 {
+		progress := 100
+		if len(status.Items) > 0 {
+			progress = getProgress(
+				completed,
+				status.TotalStringCount*len(status.Items),
+			)
+		}
+
+		check := StatusCheck{
+			FileURI:   uri,
+			Rule:      "min-file-progress",
+			Progress:  progress,
+			Threshold: gate.MinFileProgress,
+		}
+
+		if progress < gate.MinFileProgress {
+			check.Message = fmt.Sprintf(
+				"file progress %d%% is below %d%%",
+				progress,
+				gate.MinFileProgress,
+			)
+		}
+
+		gate.checks = append(gate.checks, check)
+	}
+}
+
+// getProgress returns progress in percents; file without strings is
+// considered completely translated.
+func getProgress(completed int, total int) int {
+	if total <= 0 {
+		return 100
+	}
+
+	return int(100 * float64(completed) / float64(total))
+}
+
+func (gate *StatusGate) Failures() []StatusCheck {
+	gate.Lock()
+	defer gate.Unlock()
+
+	var failures []StatusCheck
+
+	for _, check := range gate.checks {
+		if !check.Passed() {
+			failures = append(failures, check)
+		}
+	}
+
+	return failures
+}
+
+func (gate *StatusGate) Render(writer io.Writer) error {
+	failures := gate.Failures()
+	if len(failures) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(writer, "\nTranslation thresholds are not met:")
+
+	table := NewTableWriter(writer)
+
+	for _, check := range failures {
+		locale := check.Locale
+		if locale == "" {
+			locale = "*"
+		}
+
+		fmt.Fprintf(
+			table,
+			"%s\t%s\t%s\t%s\n",
+			check.FileURI,
+			locale,
+			check.Rule,
+			check.Message,
+		)
+	}
+
+	return RenderTable(table)
+}
+
+// Err returns error if any check is failed.
+func (gate *StatusGate) Err() error {
+	failures := gate.Failures()
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return NewError(
+		fmt.Errorf(
+			"%d file/locale pair(s) do not meet translation thresholds",
+			len(failures),
+		),
+
+		`See report above for offending files and locales.`,
+	)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+// WriteJUnit writes all checks as JUnit XML test cases into specified file.
+func (gate *StatusGate) WriteJUnit(path string) error {
+	gate.Lock()
+
+	suite := junitTestSuite{
+		Name:  "smartling files status",
+		Tests: len(gate.checks),
+	}
+
+	for _, check := range gate.checks {
+		name := check.Rule
+		if check.Locale != "" {
+			name = check.Locale + " " + check.Rule
+		}
+
+		testCase := junitTestCase{
+			ClassName: check.FileURI,
+			Name:      name,
+		}
+
+		if !check.Passed() {
+			suite.Failures++
+
+			testCase.Failure = &junitFailure{
+				Message: check.Message,
+				Type:    check.Rule,
+			}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	gate.Unlock()
+
+	contents, err := xml.MarshalIndent(
+		junitTestSuites{Suites: []junitTestSuite{suite}},
+		"",
+		"  ",
+	)
+	if err != nil {
+		return hierr.Errorf(err, "unable to encode JUnit report")
+	}
+
+	err = ioutil.WriteFile(path, append([]byte(xml.Header), contents...), 0644)
+	if err != nil {
+		return NewError(
+			hierr.Errorf(err, `unable to write JUnit report "%s"`, path),
+			`Check that specified path is writable.`,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	smartling "github.com/Smartling/api-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusGate(t *testing.T) {
+	gate, err := NewStatusGate(map[string]interface{}{
+		"--min-progress":      []string{"50", "de-DE=100%"},
+		"--min-file-progress": "80",
+		"--require-locale":    []string{"fr-FR", "es"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, gate.Enabled())
+
+	gate.Check(&smartling.FileStatus{
+		File: smartling.File{
+			FileURI: "messages.json",
+		},
+		TotalStringCount: 10,
+		Items: []smartling.FileStatusTranslation{
+			{LocaleID: "de-DE", CompletedStringCount: 9},
+			{LocaleID: "fr-FR", CompletedStringCount: 10},
+		},
+	})
+
+	var failures []string
+	for _, check := range gate.Failures() {
+		failures = append(failures, check.Locale+" "+check.Rule)
+	}
+
+	assert.Equal(
+		t,
+		[]string{"de-DE min-progress", "es require-locale"},
+		failures,
+	)
+	assert.Error(t, gate.Err())
+
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	assert.NoError(t, gate.WriteJUnit(path))
+
+	contents, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(contents), `tests="5" failures="2"`))
+}
+
+func TestNewStatusGateInvalidPercents(t *testing.T) {
+	_, err := NewStatusGate(map[string]interface{}{
+		"--min-progress": []string{"de-DE=150"},
+	})
+	assert.Error(t, err)
+}
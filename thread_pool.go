@@ -1,30 +1,49 @@
 package main
 
 import (
+	"context"
 	"sync"
 )
 
 type ThreadPool struct {
+	ctx       context.Context
 	available chan struct{}
 	size      int
 	group     sync.WaitGroup
 }
 
-func NewThreadPool(size int) *ThreadPool {
+func NewThreadPool(ctx context.Context, size int) *ThreadPool {
+	if size < 1 {
+		size = 1
+	}
+
 	available := make(chan struct{}, size)
 	for i := 0; i < size; i++ {
 		available <- struct{}{}
 	}
 
 	return &ThreadPool{
+		ctx:       ctx,
 		group:     sync.WaitGroup{},
 		available: available,
 		size:      size,
 	}
 }
 
-func (pool *ThreadPool) Do(task func()) {
-	<-pool.available
+// Do runs task as soon as there is available thread. Task is not run and
+// false is returned if pool context is done before that.
+func (pool *ThreadPool) Do(task func()) bool {
+	select {
+	case <-pool.available:
+	case <-pool.ctx.Done():
+		return false
+	}
+
+	if pool.ctx.Err() != nil {
+		pool.available <- struct{}{}
+
+		return false
+	}
 
 	pool.group.Add(1)
 
@@ -36,6 +55,8 @@ func (pool *ThreadPool) Do(task func()) {
 
 		task()
 	}()
+
+	return true
 }
 
 func (pool *ThreadPool) Wait() {
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/reconquest/hierr-go"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// knownDirectives lists Smartling file directives which are accepted in
+// push.directives section; unknown directives are reported as warnings,
+// because API can accept directives which are not listed there.
+var knownDirectives = map[string]bool{
+	"namespace":                 true,
+	"file_charset":              true,
+	"placeholder_format":        true,
+	"placeholder_format_custom": true,
+	"string_format":             true,
+	"string_format_paths":       true,
+	"source_key_paths":          true,
+	"translate_paths":           true,
+	"translate_mode":            true,
+	"key_paths":                 true,
+	"exclude_key_paths":         true,
+	"include_key_paths":         true,
+	"variants_enabled":          true,
+	"whitespace_trim":           true,
+	"entity_escaping":           true,
+	"plurals_detection":         true,
+	"pseudo_inflation":          true,
+	"sltrans":                   true,
+	"client_lib_id":             true,
+	"character_limit_paths":     true,
+	"instruction_paths":         true,
+	"translate_attributes":      true,
+	"no_translate_attributes":   true,
+	"yaml_locale_substitution":  true,
+	"content_type":              true,
+}
+
+// ConfigIssue is a problem found in config file by validateConfig.
+type ConfigIssue struct {
+	Line    int
+	Key     string
+	Warning bool
+	Message string
+}
+
+func (issue ConfigIssue) String() string {
+	level := "error"
+	if issue.Warning {
+		level = "warning"
+	}
+
+	key := ""
+	if issue.Key != "" {
+		key = fmt.Sprintf(" %q:", issue.Key)
+	}
+
+	line := ""
+	if issue.Line > 0 {
+		line = fmt.Sprintf("%d:", issue.Line)
+	}
+
+	return fmt.Sprintf("%s %s:%s %s", line, level, key, issue.Message)
+}
+
+// validateConfig checks config file offline without making any API calls
+// and returns all found issues sorted by line.
+func validateConfig(path string) ([]ConfigIssue, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, NewError(
+			hierr.Errorf(err, `unable to read config file "%s"`, path),
+			`Check that config file exists and readable by current user.`,
+		)
+	}
+
+	var (
+		document yamlv3.Node
+		issues   []ConfigIssue
+	)
+
+	err = yamlv3.Unmarshal(contents, &document)
+	if err != nil {
+		return []ConfigIssue{{Message: err.Error()}}, nil
+	}
+
+	if document.Kind == 0 || len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	root := document.Content[0]
+
+	checkConfigKeys(root, reflect.TypeOf(Config{}), "", &issues)
+
+	// values are decoded same way as in runtime to find type mismatches
+	_, err = NewConfig(path)
+	switch err := err.(type) {
+	case nil:
+
+	case MissingConfigVariableError:
+		// variable can be set in environment where config is used
+		issues = append(issues, ConfigIssue{
+			Line:    err.Line,
+			Key:     err.KeyName,
+			Warning: true,
+			Message: fmt.Sprintf(
+				"environment variable $%s is not set",
+				err.VarName,
+			),
+		})
+
+	case *yamlv3.TypeError:
+		for _, message := range err.Errors {
+			var issue ConfigIssue
+
+			_, scanErr := fmt.Sscanf(message, "line %d:", &issue.Line)
+			if scanErr == nil {
+				message = strings.TrimSpace(
+					strings.SplitN(message, ":", 2)[1],
+				)
+			}
+
+			issue.Message = message
+
+			issues = append(issues, issue)
+		}
+
+	default:
+		issues = append(issues, ConfigIssue{
+			Message: compactError(err),
+		})
+	}
+
+	checkConfigFiles(getConfigNode(root, "files"), &issues)
+	checkConfigLocales(getConfigNode(root, "locales"), "locales", &issues)
+
+	if profiles := getConfigNode(root, "profiles"); profiles != nil &&
+		profiles.Kind == yamlv3.MappingNode {
+		for i := 0; i+1 < len(profiles.Content); i += 2 {
+			name := "profiles." + profiles.Content[i].Value + ".locales"
+
+			checkConfigLocales(
+				getConfigNode(profiles.Content[i+1], "locales"),
+				name,
+				&issues,
+			)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+
+	return issues, nil
+}
+
+// checkConfigKeys reports keys which don't correspond to any field of
+// config structures, since they are silently ignored while loading.
+func checkConfigKeys(
+	node *yamlv3.Node,
+	kind reflect.Type,
+	key string,
+	issues *[]ConfigIssue,
+) {
+	for kind.Kind() == reflect.Ptr {
+		kind = kind.Elem()
+	}
+
+	switch kind.Kind() {
+	case reflect.Struct:
+		if node.Kind != yamlv3.MappingNode {
+			return
+		}
+
+		fields := getConfigFields(kind)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name := node.Content[i].Value
+
+			field, ok := fields[name]
+			if !ok {
+				*issues = append(*issues, ConfigIssue{
+					Line:    node.Content[i].Line,
+					Key:     joinConfigKey(key, name),
+					Message: "unknown key",
+				})
+
+				continue
+			}
+
+			checkConfigKeys(
+				node.Content[i+1],
+				field,
+				joinConfigKey(key, name),
+				issues,
+			)
+		}
+
+	case reflect.Map:
+		if node.Kind != yamlv3.MappingNode {
+			return
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkConfigKeys(
+				node.Content[i+1],
+				kind.Elem(),
+				joinConfigKey(key, node.Content[i].Value),
+				issues,
+			)
+		}
+
+	case reflect.Slice:
+		if node.Kind != yamlv3.SequenceNode {
+			return
+		}
+
+		for i, item := range node.Content {
+			checkConfigKeys(
+				item,
+				kind.Elem(),
+				fmt.Sprintf("%s[%d]", key, i),
+				issues,
+			)
+		}
+	}
+}
+
+// getConfigFields returns types of struct fields keyed by YAML key.
+func getConfigFields(kind reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := 0; i < kind.NumField(); i++ {
+		field := kind.Field(i)
+
+		if field.PkgPath != "" || field.Anonymous {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+
+		switch name {
+		case "-":
+			continue
+
+		case "":
+			name = strings.ToLower(field.Name)
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+func checkConfigFiles(files *yamlv3.Node, issues *[]ConfigIssue) {
+	if files == nil || files.Kind != yamlv3.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(files.Content); i += 2 {
+		var (
+			pattern = files.Content[i]
+			section = files.Content[i+1]
+			key     = joinConfigKey("files", pattern.Value)
+		)
+
+		if pattern.Value != "default" {
+			_, err := glob.Compile(pattern.Value, '/')
+			if err != nil {
+				*issues = append(*issues, ConfigIssue{
+					Line:    pattern.Line,
+					Key:     key,
+					Message: "invalid file pattern: " + err.Error(),
+				})
+			}
+		}
+
+		format := getConfigNode(getConfigNode(section, "pull"), "format")
+		if format != nil && format.Value != "" {
+			_, err := compileFormat(format.Value)
+			if err, ok := err.(Error); ok {
+				*issues = append(*issues, ConfigIssue{
+					Line:    format.Line,
+					Key:     key + ".pull.format",
+					Message: "invalid format: " + compactError(err.Cause),
+				})
+			}
+		}
+
+		checkConfigLocales(
+			getConfigNode(section, "locales"),
+			key+".locales",
+			issues,
+		)
+
+		directives := getConfigNode(getConfigNode(section, "push"), "directives")
+		if directives == nil || directives.Kind != yamlv3.MappingNode {
+			continue
+		}
+
+		for j := 0; j+1 < len(directives.Content); j += 2 {
+			name := directives.Content[j].Value
+
+			if !knownDirectives[strings.TrimPrefix(name, "smartling.")] {
+				*issues = append(*issues, ConfigIssue{
+					Line:    directives.Content[j].Line,
+					Key:     key + ".push.directives." + name,
+					Warning: true,
+					Message: "unknown directive",
+				})
+			}
+		}
+	}
+}
+
+// checkConfigLocales reports empty, duplicate and conflicting locale
+// mappings; every application locale should map to exactly one Smartling
+// locale and vice versa.
+func checkConfigLocales(
+	locales *yamlv3.Node,
+	key string,
+	issues *[]ConfigIssue,
+) {
+	if locales == nil || locales.Kind != yamlv3.SequenceNode {
+		return
+	}
+
+	var (
+		applications = map[string]*yamlv3.Node{}
+		smartlings   = map[string]*yamlv3.Node{}
+	)
+
+	for i, item := range locales.Content {
+		var (
+			itemKey     = fmt.Sprintf("%s[%d]", key, i)
+			application = getConfigNode(item, "application")
+			smartling   = getConfigNode(item, "smartling")
+		)
+
+		if application == nil || application.Value == "" ||
+			smartling == nil || smartling.Value == "" {
+			*issues = append(*issues, ConfigIssue{
+				Line:    item.Line,
+				Key:     itemKey,
+				Message: "both application and smartling locales are required",
+			})
+
+			continue
+		}
+
+		if previous, ok := applications[application.Value]; ok {
+			message := fmt.Sprintf(
+				"duplicate application locale %q, already defined on line %d",
+				application.Value,
+				previous.Line,
+			)
+
+			if previous.Value != smartling.Value {
+				message = fmt.Sprintf(
+					"application locale %q is mapped to both %q (line %d) and %q",
+					application.Value,
+					previous.Value,
+					previous.Line,
+					smartling.Value,
+				)
+			}
+
+			*issues = append(*issues, ConfigIssue{
+				Line:    application.Line,
+				Key:     itemKey,
+				Message: message,
+			})
+		} else {
+			applications[application.Value] = smartling
+		}
+
+		if previous, ok := smartlings[smartling.Value]; ok &&
+			previous.Value != application.Value {
+			*issues = append(*issues, ConfigIssue{
+				Line: smartling.Line,
+				Key:  itemKey,
+				Message: fmt.Sprintf(
+					"smartling locale %q is mapped to both %q (line %d) and %q",
+					smartling.Value,
+					previous.Value,
+					previous.Line,
+					application.Value,
+				),
+			})
+		} else if !ok {
+			smartlings[smartling.Value] = application
+		}
+	}
+}
+
+func getConfigNode(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func joinConfigKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartling.yml")
+
+	err := ioutil.WriteFile(path, []byte(`user_id: "user"
+project_id: "project"
+threds: 4
+locales:
+    - application: de
+      smartling: de-DE
+    - application: de
+      smartling: de-AT
+    - application: fr
+files:
+    "[a-z.json":
+        pull:
+            format: "{{name .FileURI"
+    "**.json":
+        push:
+            directives:
+                namespace: "app"
+                file_charsett: "utf-8"
+        pul:
+            format: "{{.Locale}}.json"
+`), 0644)
+	assert.NoError(t, err)
+
+	issues, err := validateConfig(path)
+	assert.NoError(t, err)
+
+	var lines []int
+	for _, issue := range issues {
+		lines = append(lines, issue.Line)
+	}
+
+	assert.Equal(t, []int{3, 7, 9, 11, 13, 18, 19}, lines)
+	assert.True(t, issues[5].Warning)
+	assert.Equal(t, "files.**.json.pul", issues[6].Key)
+}